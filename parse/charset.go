@@ -0,0 +1,152 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dhodges/sgfinfo/sgf"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// cp1252 maps the Windows-1252 bytes 0x80-0x9F onto Unicode; zero marks
+// bytes the code page leaves undefined. Bytes from 0xA0 up map directly.
+var cp1252 = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// repairCP1252 transcodes the bytes of s that aren't valid UTF-8 as
+// Windows-1252, leaving valid sequences alone. ok is false if any such
+// byte is undefined in Windows-1252.
+func repairCP1252(s string) (repaired string, ok bool) {
+	runes := []rune{}
+	for len(s) > 0 {
+		r, width := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && width == 1 {
+			b := s[0]
+			switch {
+			case b >= 0xA0:
+				r = rune(b)
+			case b >= 0x80 && cp1252[b-0x80] != 0:
+				r = cp1252[b-0x80]
+			default:
+				return "", false
+			}
+		}
+		runes = append(runes, r)
+		s = s[width:]
+	}
+	return string(runes), true
+}
+
+var charsetPattern = regexp.MustCompile(`(?:^|[^A-Za-z])CA\s*\[([^\]\\]*)\]`)
+
+// decodeInput converts the whole input to UTF-8 before it is lexed when
+// every CA in it names the same known charset, so that multibyte charsets
+// whose second bytes can be '\\' or ']', such as Shift_JIS, are read
+// correctly. It returns the charset decoded from, or "" if input was left
+// for decodeCharset to handle game by game.
+func decodeInput(input string) (string, string) {
+	name := ""
+	for _, match := range charsetPattern.FindAllStringSubmatch(input, -1) {
+		ca := strings.TrimSpace(match[1])
+		if name != "" && !strings.EqualFold(ca, name) {
+			return input, ""
+		}
+		name = ca
+	}
+	if isUTF8Charset(name) {
+		return input, ""
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return input, ""
+	}
+	decoded, err := enc.NewDecoder().String(input)
+	if err != nil {
+		return input, ""
+	}
+	return decoded, name
+}
+
+// decodeCharset converts the text values of game from the charset named by
+// its CA property into UTF-8, and then sets CA to UTF-8 so the game is
+// written back out consistently. Input already decoded from the charset
+// by decodeInput only needs CA updating. Otherwise only text values are
+// converted, since the rest of the file is ASCII; this can't be relied on
+// for a charset whose second bytes may be ASCII, so that is recorded as an
+// error, as is an unknown charset, and the values are left as they are.
+func decodeCharset(game *sgf.Game, decodedFrom string) {
+	name := strings.TrimSpace(game.GameInfo[sgf.Charset])
+	if isUTF8Charset(name) {
+		return
+	}
+	if decodedFrom != "" && strings.EqualFold(name, decodedFrom) {
+		game.GameInfo[sgf.Charset] = "UTF-8"
+		return
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		game.AddError(fmt.Sprintf("CA[%s]: unknown charset", name))
+		return
+	}
+	if canonical, _ := htmlindex.Name(enc); asciiTrailBytes[canonical] {
+		game.AddError(fmt.Sprintf("CA[%s]: can't be read in a file mixing charsets", name))
+		return
+	}
+
+	decode := func(prop sgf.Property) string {
+		if !hasTextValue(prop.Name) {
+			return prop.Value
+		}
+		value, err := enc.NewDecoder().String(prop.Value)
+		if err != nil {
+			game.AddError(fmt.Sprintf("property %s: can't decode as %s", prop.Name, name))
+			return prop.Value
+		}
+		return value
+	}
+	for key, value := range game.GameInfo {
+		game.GameInfo[key] = decode(sgf.Property{Name: key, Value: value})
+	}
+	var decodeNode func(node *sgf.Node)
+	decodeNode = func(node *sgf.Node) {
+		for ; node != nil; node = node.Next {
+			for i, prop := range node.Properties {
+				node.Properties[i].Value = decode(prop)
+			}
+			for _, nodevar := range node.Variations {
+				decodeNode(nodevar)
+			}
+		}
+	}
+	decodeNode(game.GameTree)
+	game.GameInfo[sgf.Charset] = "UTF-8"
+}
+
+// asciiTrailBytes lists, by their htmlindex names, the multibyte charsets
+// whose second bytes can fall in the ASCII range.
+var asciiTrailBytes = map[string]bool{
+	"shift_jis": true, "big5": true, "gbk": true, "gb18030": true,
+}
+
+// isUTF8Charset reports whether a CA value leaves text as UTF-8, which is
+// also the default when CA is missing.
+func isUTF8Charset(name string) bool {
+	switch strings.ToLower(strings.Replace(name, "-", "", -1)) {
+	case "", "utf8", "usascii", "ascii":
+		return true
+	}
+	return false
+}
+
+// hasTextValue reports whether name takes a text or simpletext value, or
+// a composed value including one.
+func hasTextValue(name string) bool {
+	spec, ok := sgf.PropertyInfo(name)
+	return ok && strings.Contains(spec.ValueType, "text")
+}
@@ -0,0 +1,45 @@
+package parse
+
+import "github.com/dhodges/sgfinfo/sgf"
+
+// ParserConfig adjusts how forgiving the parser is of malformed input.
+// The zero value gives the default strict behaviour.
+type ParserConfig struct {
+	// Lenient repairs recoverable problems, recording a warning on the
+	// game for each repair.
+	Lenient bool
+
+	// TrimSimpleText strips leading and trailing whitespace from
+	// single-line game-info values such as player names.
+	TrimSimpleText bool
+
+	// LexOptions are passed on to the lexer. With MovesOnly the tree
+	// structure, moves and setup stones are kept but every other property
+	// below the root is skipped while lexing, to save memory.
+	LexOptions
+
+	// SplitNestedGames, in lenient mode, splits out a variation that opens
+	// with game-info properties as a game of its own, taking it to be a
+	// game nested by mistake. Otherwise it is kept as a variation. Either
+	// way a warning is recorded.
+	SplitNestedGames bool
+
+	// RecoverErrors carries on past a syntax error from the next node or
+	// parenthesis, so that every error in the input is recorded on the
+	// game rather than just the first.
+	RecoverErrors bool
+
+	// MaxErrors caps the errors recorded while recovering, so that a badly
+	// corrupt file cannot run on for thousands of them. Parsing stops once
+	// the cap is reached, leaving a final "too many errors" error on the
+	// game. Zero means DefaultMaxErrors.
+	MaxErrors int
+
+	// PointCodec, if set, reads moves and points written in its encoding
+	// rather than SGF's letters, converting them to letters as they are
+	// parsed. Values it cannot read are kept as they are.
+	PointCodec sgf.PointCodec
+}
+
+// DefaultMaxErrors is the cap on recovered errors when MaxErrors is unset.
+const DefaultMaxErrors = 100
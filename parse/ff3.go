@@ -0,0 +1,35 @@
+package parse
+
+import "github.com/dhodges/sgfinfo/sgf"
+
+// ff3Names maps FF[3] property names onto their FF[4] replacements. They
+// are translated in every node, the root included, once FF[3] has been
+// read.
+var ff3Names = map[string]string{
+	"L": "LB",
+	"M": "MA",
+}
+
+// ff3Removed lists the FF[3] properties FF[4] dropped without a
+// replacement, such as EL (computer evaluation) and EX (expected move).
+// There is nothing to translate them to, so they are kept as read, which
+// FF[4] readers skip, and a warning is recorded for each.
+var ff3Removed = map[string]bool{
+	"BS": true, "CH": true, "EL": true, "EX": true, "ID": true, "LT": true, "OM": true,
+	"OP": true, "OV": true, "RG": true, "SC": true, "SI": true, "TC": true, "WS": true,
+}
+
+// ff3Property translates an FF[3] property, given the index of the value
+// within it. FF[3] labels points with successive letters where LB gives
+// each its text.
+func ff3Property(prop sgf.Property, n int) sgf.Property {
+	name, ok := ff3Names[prop.Name]
+	if !ok {
+		return prop
+	}
+	if prop.Name == "L" {
+		prop.Value += ":" + string(rune('a'+n%26))
+	}
+	prop.Name = name
+	return prop
+}
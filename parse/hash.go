@@ -0,0 +1,44 @@
+package parse
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+)
+
+// HashGame hashes the moves, nodes and variation structure of the SGF read
+// from r, ignoring comments and all other properties. The input is lexed
+// as it is read, so neither the whole file nor a game tree is held in
+// memory.
+func HashGame(r io.Reader) (uint64, error) {
+	h := fnv.New64a()
+	l := lexReader(r)
+	defer l.close()
+	name := ""
+	for {
+		i := l.nextItem()
+		switch i.typ {
+		case itemLeftParen:
+			h.Write([]byte("("))
+		case itemRightParen:
+			h.Write([]byte(")"))
+		case itemSemiColon:
+			h.Write([]byte(";"))
+		case itemPropertyName:
+			name = i.val
+		case itemPropertyValue:
+			if name == "B" || name == "W" {
+				h.Write([]byte(name + "[" + i.val + "]"))
+			}
+		case itemError, itemEOF:
+			// a read error cuts the input short, so it comes first
+			if l.readErr != nil {
+				return 0, l.readErr
+			}
+			if i.typ == itemError {
+				return 0, errors.New(i.val)
+			}
+			return h.Sum64(), nil
+		}
+	}
+}
@@ -8,7 +8,10 @@
 package parse
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"runtime"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -31,14 +34,30 @@ type stateFn func(*lexer) stateFn
 
 // lexer holds the state of the scanner.
 type lexer struct {
-	name    string    // the name of the input; used only for error reports
-	input   string    // the string being scanned
-	state   stateFn   // the next lexing function to enter
-	pos     Pos       // current position in the input
-	start   Pos       // start position of this item
-	width   Pos       // width of last rune read from input
-	lastPos Pos       // position of most recent item returned by nextItem
-	items   chan item // channel of scanned items
+	name    string        // the name of the input; used only for error reports
+	input   string        // the string being scanned
+	state   stateFn       // the next lexing function to enter
+	pos     Pos           // current position in the input
+	start   Pos           // start position of this item
+	width   Pos           // width of last rune read from input
+	lastPos Pos           // position of most recent item returned by nextItem
+	items   chan item     // channel of scanned items
+	done    chan struct{} // closed when the reader stops reading items
+	resync  bool          // carry on after an error from the next node
+	opts    LexOptions
+
+	depth    int  // parentheses open in the current game
+	nodes    int  // nodes started at depth 1, the first being the root
+	skipping bool // the values of the current property are left out
+
+	// A lexer reading from r holds only the input from the start of the
+	// current item on; offset, line and col place input[0] in the whole.
+	r       *bufio.Reader
+	readErr error // the error, other than io.EOF, that ended reading r
+	offset  Pos
+	line    int
+	col     int
+	afterCR bool // the input dropped so far ends in '\r'
 }
 
 const (
@@ -71,12 +90,6 @@ func succinct(s string) string {
 	}
 }
 
-func strip_newlines(s string) string {
-	result := strings.Replace(s, "\n", "", -1)
-	result = strings.Replace(result, "\r", "", -1)
-	return result
-}
-
 func (i item) String() string {
 	switch i.typ {
 	case itemEOF:
@@ -112,6 +125,9 @@ func (i itemType) String() string {
 
 // next returns the next rune in the input.
 func (l *lexer) next() rune {
+	if len(l.input)-int(l.pos) < utf8.UTFMax {
+		l.fill()
+	}
 	if int(l.pos) >= len(l.input) {
 		l.width = 0
 		return eof
@@ -122,6 +138,38 @@ func (l *lexer) next() rune {
 	return r
 }
 
+// fill reads more of r into the input, first dropping the input before
+// the current item.
+func (l *lexer) fill() {
+	if l.r == nil || l.readErr != nil {
+		return
+	}
+	for _, r := range l.input[:l.start] {
+		switch {
+		case r == '\n' && l.afterCR:
+		case isEndOfLine(r):
+			l.line, l.col = l.line+1, 1
+		default:
+			l.col += 1
+		}
+		l.afterCR = r == '\r'
+	}
+	l.input = l.input[l.start:]
+	l.offset += l.start
+	l.pos -= l.start
+	l.start = 0
+
+	buf := make([]byte, 4096)
+	n, err := io.ReadAtLeast(l.r, buf, utf8.UTFMax)
+	l.input += string(buf[:n])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		l.readErr = err
+	}
+	if err != nil {
+		l.r = nil
+	}
+}
+
 // peek returns but does not consume the next rune in the input.
 func (l *lexer) peek() rune {
 	r := l.next()
@@ -136,14 +184,33 @@ func (l *lexer) backup() {
 
 // emit passes an item back to the client.
 func (l *lexer) emit(t itemType) {
-	i := item{t, l.start, l.input[l.start:l.pos]}
-	if i.typ == itemPropertyName {
+	i := item{t, l.offset + l.start, l.input[l.start:l.pos]}
+	switch i.typ {
+	case itemPropertyName:
 		i.val = strings.ToUpper(i.val)
+	case itemPropertyValue:
+		i.val = unescape(i.val)
 	}
-	l.items <- i
+	l.send(i)
 	l.start = l.pos
 }
 
+// send passes an item to the reader, or ends the scan if the reader has
+// stopped listening.
+func (l *lexer) send(i item) {
+	select {
+	case l.items <- i:
+	case <-l.done:
+		runtime.Goexit()
+	}
+}
+
+// close tells the scanner that no more items will be read, so that it
+// can stop.
+func (l *lexer) close() {
+	close(l.done)
+}
+
 // ignore skips over the pending input before this point.
 func (l *lexer) ignore() {
 	l.start = l.pos
@@ -155,20 +222,53 @@ func (l *lexer) advance() {
 	l.ignore()
 }
 
-func (l *lexer) quoteContext() string {
-	start := l.pos - 6
+// quoteContext shows the input around pos, marking pos with "|".
+func (l *lexer) quoteContext(pos Pos) string {
+	pos -= l.offset
+	start := pos - 6
 	if start < 0 {
 		start = 0
 	}
-	end := int(l.pos + 6)
-	if end >= len(l.input) {
-		end = len(l.input) - 1
+	end := int(pos + 6)
+	if end > len(l.input) {
+		end = len(l.input)
 	}
-	return l.input[start:l.pos] + "|" + l.input[l.pos:end]
+	return l.input[start:pos] + "|" + l.input[pos:end]
 }
 
 func (l *lexer) QuoteErrorContext(message string) string {
-	return fmt.Sprintf("%s, position %d, %q", message, l.pos, l.quoteContext())
+	return l.quoteErrorAt(l.offset+l.pos, message)
+}
+
+// quoteErrorAt gives message with the line, column and context of pos.
+// It reads only the input, so the parser may call it while the lexer runs,
+// unless the lexer is reading from r.
+func (l *lexer) quoteErrorAt(pos Pos, message string) string {
+	return fmt.Sprintf("%s: %s, %q", l.position(pos), message, l.quoteContext(pos))
+}
+
+// position gives pos as a 1-based line and column, counting "\n", "\r\n"
+// and a lone "\r" as line breaks.
+func (l *lexer) position(pos Pos) string {
+	line, col := l.line, l.col
+	for i, r := range l.input[:pos-l.offset] {
+		switch {
+		case r == '\n' && (i > 0 && l.input[i-1] == '\r' || i == 0 && l.afterCR):
+		case isEndOfLine(r):
+			line, col = line+1, 1
+		default:
+			col += 1
+		}
+	}
+	return fmt.Sprintf("line %d, col %d", line, col)
+}
+
+// skipWhiteSpace drops any whitespace at the current position.
+func (l *lexer) skipWhiteSpace() {
+	for isWhiteSpace(l.peek()) {
+		l.next()
+	}
+	l.ignore()
 }
 
 // acceptAlphaRun consumes a run of alphabeticals from the valid set.
@@ -178,17 +278,33 @@ func (l *lexer) acceptAlphaRun() {
 	l.backup()
 }
 
-// acceptPropertyValue consumes a run of alphabeticals from the valid set.
-func (l *lexer) acceptPropertyValueRun() {
-	for isPropertyValueChar(l.next()) {
+// acceptPropertyValueRun consumes the text of a property value. A
+// backslash escapes the rune after it, so an escaped ']' does not end the
+// value. It returns false if the input ends straight after a backslash.
+func (l *lexer) acceptPropertyValueRun() bool {
+	for {
+		r := l.next()
+		if r == '\\' {
+			if l.next() == eof {
+				return false
+			}
+			continue
+		}
+		if !isPropertyValueChar(r) {
+			l.backup()
+			return true
+		}
 	}
-	l.backup()
 }
 
 // errorf returns an error token and terminates the scan by passing
 // back a nil pointer that will be the next state, terminating l.nextItem.
+// A resyncing lexer goes on to lexResync instead.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
-	l.items <- item{itemError, l.start, fmt.Sprintf(format, args...)}
+	l.send(item{itemError, l.offset + l.start, fmt.Sprintf(format, args...)})
+	if l.resync {
+		return lexResync
+	}
 	return nil
 }
 
@@ -201,25 +317,67 @@ func (l *lexer) nextItem() item {
 
 // run the state machine for the lexer.
 func (l *lexer) run() {
-	for l.state = lexBegin; l.state != nil; {
+	for l.state != nil {
 		l.state = l.state(l)
 	}
 }
 
 // lex creates a new scanner for the input string.
 func lex(input string) *lexer {
-	l := &lexer{
-		input: strip_newlines(input),
-		items: make(chan item),
-	}
+	return lexFrom(input, 0, lexBegin)
+}
+
+// lexResyncing creates a scanner for the input string that reports an
+// error and carries on from the next node or parenthesis.
+func lexResyncing(input string) *lexer {
+	l := newLexer(input, 0, lexBegin)
+	l.resync = true
+	go l.run()
+	return l
+}
+
+// lexReader creates a scanner that reads its input from r as it goes,
+// rather than holding all of it.
+func lexReader(r io.Reader) *lexer {
+	l := newLexer("", 0, lexBegin)
+	l.r = bufio.NewReader(r)
 	go l.run()
 	return l
 }
 
+// lexWithOptions creates a scanner for the input string that follows
+// opts.
+func lexWithOptions(input string, opts LexOptions) *lexer {
+	l := newLexer(input, 0, lexBegin)
+	l.opts = opts
+	go l.run()
+	return l
+}
+
+// lexFrom creates a scanner that starts in state at pos.
+func lexFrom(input string, pos Pos, state stateFn) *lexer {
+	l := newLexer(input, pos, state)
+	go l.run()
+	return l
+}
+
+func newLexer(input string, pos Pos, state stateFn) *lexer {
+	return &lexer{
+		input: input,
+		state: state,
+		pos:   pos,
+		start: pos,
+		items: make(chan item),
+		done:  make(chan struct{}),
+		line:  1,
+		col:   1,
+	}
+}
+
 // lexBegin scans until an opening left parenthesis "(".
 func lexBegin(l *lexer) stateFn {
 	for {
-		if strings.HasPrefix(l.input[l.pos:], "(") {
+		if l.peek() == '(' {
 			return lexLeftParen
 		}
 		if l.next() == eof {
@@ -233,16 +391,25 @@ func lexBegin(l *lexer) stateFn {
 
 func lexLeftParen(l *lexer) stateFn {
 	l.pos += Pos(len("("))
+	l.depth += 1
+	if l.depth == 1 {
+		l.nodes = 0
+	}
 	l.emit(itemLeftParen)
+	l.skipWhiteSpace()
 	if l.peek() != ';' {
-		return l.errorf(l.QuoteErrorContext("semi-colon expected here"))
+		return l.errorf("%s", l.QuoteErrorContext("semi-colon expected here"))
 	}
 	return lexSemiColon
 }
 
 func lexRightParen(l *lexer) stateFn {
 	l.pos += Pos(len(")"))
+	if l.depth > 0 {
+		l.depth -= 1
+	}
 	l.emit(itemRightParen)
+	l.skipWhiteSpace()
 
 	switch {
 	case l.peek() == '(':
@@ -261,38 +428,60 @@ func lexRightParen(l *lexer) stateFn {
 
 func lexSemiColon(l *lexer) stateFn {
 	l.pos += Pos(len(";"))
+	if l.depth == 1 {
+		l.nodes += 1
+	}
 	l.emit(itemSemiColon)
+	l.skipWhiteSpace()
 	if l.peek() == ';' {
 		l.advance()
+		l.skipWhiteSpace()
+	}
+	// an empty node, as in the minimal game "(;)"
+	switch l.peek() {
+	case ')':
+		return lexRightParen
+	case '(':
+		return lexLeftParen
 	}
 	if !isAlpha(l.peek()) {
-		return l.errorf(l.QuoteErrorContext("property expected here"))
+		return l.errorf("%s", l.QuoteErrorContext("property expected here"))
 	}
 	return lexPropertyName
 }
 
 func lexPropertyName(l *lexer) stateFn {
 	l.acceptAlphaRun()
-	l.emit(itemPropertyName)
+	inRoot := l.depth == 1 && l.nodes == 1
+	l.skipping = l.opts.MovesOnly && !inRoot && !isMoveOrSetupProperty(strings.ToUpper(l.input[l.start:l.pos]))
+	if l.skipping {
+		l.ignore()
+	} else {
+		l.emit(itemPropertyName)
+	}
+	l.skipWhiteSpace()
 	if (l.peek()) != '[' {
-		return l.errorf(l.QuoteErrorContext("left bracket '[' expected here"))
+		return l.errorf("%s", l.QuoteErrorContext("left bracket '[' expected here"))
 	}
 	return lexLeftBracket
 }
 
 func lexLeftBracket(l *lexer) stateFn {
 	l.advance()
-	l.acceptPropertyValueRun()
-	l.emit(itemPropertyValue)
+	if !l.acceptPropertyValueRun() {
+		return l.errorf("%s: input ends in an escape", l.position(l.offset+l.pos))
+	}
+	if l.skipping {
+		l.ignore()
+	} else {
+		l.emit(itemPropertyValue)
+	}
 
 	if l.peek() != ']' {
-		return l.errorf("right bracket ']' expected here (position: %d)", l.pos)
+		return l.errorf("%s: right bracket ']' expected", l.position(l.offset+l.pos))
 	}
 	l.advance()
-
-	for isWhiteSpace(l.peek()) {
-		l.next()
-	}
+	l.skipWhiteSpace()
 
 	switch l.peek() {
 	case '[':
@@ -309,7 +498,29 @@ func lexLeftBracket(l *lexer) stateFn {
 		return lexPropertyName
 	}
 
-	return l.errorf("property or node or parenthesis expected here (position: %d). Found: %q", l.pos, l.peek())
+	return l.errorf("%s: property or node or parenthesis expected, found %q", l.position(l.offset+l.pos), l.peek())
+}
+
+// lexResync skips the input after an error up to the next ';', '(' or ')'.
+func lexResync(l *lexer) stateFn {
+	for {
+		switch l.peek() {
+		case ';':
+			l.ignore()
+			return lexSemiColon
+		case '(':
+			l.ignore()
+			return lexLeftParen
+		case ')':
+			l.ignore()
+			return lexRightParen
+		case eof:
+			l.ignore()
+			l.emit(itemEOF)
+			return nil
+		}
+		l.next()
+	}
 }
 
 func isSpace(r rune) bool {
@@ -328,6 +539,34 @@ func isAlpha(r rune) bool {
 	return unicode.IsLetter(r)
 }
 
+// unescape removes the backslashes escaping characters in a value. An
+// escaped line break is a soft line break and is dropped altogether. The
+// value is worked through byte by byte, so text that isn't UTF-8 is left
+// as it is for decoding later.
+func unescape(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		if !isEndOfLine(rune(value[i])) {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+1 < len(value) && isEndOfLine(rune(value[i+1])) && value[i+1] != value[i] {
+			i++
+		}
+	}
+	return b.String()
+}
+
+// isPropertyValueChar accepts line breaks and tabs as well as printable
+// characters, since they are significant in text values.
 func isPropertyValueChar(r rune) bool {
-	return unicode.IsPrint(r) && r != ']'
+	return (unicode.IsPrint(r) || isWhiteSpace(r)) && r != ']'
 }
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"strings"
 	"testing"
 
   "github.com/stretchr/testify/assert"
@@ -27,14 +28,40 @@ type example struct {
 var invalidExamples = []example{
 	{"(;CA)", "missing left bracket '['"},
 	{"(;CA[UTF-8", "missing right bracket ']'"},
-	{"(;CA[UTF-8];)", "missing property"},
+	{"(;CA[UTF-8];[aa])", "missing property"},
 	{"(CA[UTF-8])", "missing semi-colon"},
 }
 
-func TestLexFilterNewlines(t *testing.T) {
-	l := lex("(;CA[UTF-8]\n\rSZ[19]\r\nEV[The Game of the Century]")
+func TestLexSkipsNewlinesBetweenItems(t *testing.T) {
+	l := lex("(\n;CA[UTF-8]\n\rSZ[19]\r\nEV[The Game of the Century]\n;\nB[aa]\n)\n")
 
-	assert.Equal(t, l.input, "(;CA[UTF-8]SZ[19]EV[The Game of the Century]", "expected lexer to strip newlines")
+	var names []string
+	for {
+		i := l.nextItem()
+		if i.typ == itemEOF || i.typ == itemError {
+			assert.Equal(t, i.typ, itemEOF, "unexpected error")
+			break
+		}
+		if i.typ == itemPropertyName {
+			names = append(names, i.val)
+		}
+	}
+	assert.Equal(t, names, []string{"CA", "SZ", "EV", "B"}, "wrong property names")
+}
+
+func TestLexKeepsNewlinesInValues(t *testing.T) {
+	l := lex("(;C[first paragraph\r\n\nsecond paragraph])")
+
+	for {
+		i := l.nextItem()
+		if i.typ == itemPropertyValue {
+			assert.Equal(t, i.val, "first paragraph\r\n\nsecond paragraph", "newlines lost")
+			break
+		}
+		if i.typ == itemEOF || i.typ == itemError {
+			t.Fatal("comment value not found")
+		}
+	}
 }
 
 func TestLexErrors(t *testing.T) {
@@ -50,3 +77,115 @@ func TestLexErrors(t *testing.T) {
 		assert.Equal(t, i.typ, itemError, "expected an error")
 	}
 }
+
+func lexValue(input string) item {
+	l := lex(input)
+	for {
+		i := l.nextItem()
+		if i.typ == itemPropertyValue || i.typ == itemError || i.typ == itemEOF {
+			return i
+		}
+	}
+}
+
+func TestLexEscapes(t *testing.T) {
+	assert.Equal(t, lexValue(`(;C[a\\b])`).val, `a\b`, "escaped backslash")
+	assert.Equal(t, lexValue(`(;C[x\]y])`).val, "x]y", "escaped bracket")
+	assert.Equal(t, lexValue(`(;C[he said \]end\[ of it])`).val, "he said ]end[ of it", "escaped brackets")
+	assert.Equal(t, lexValue(`(;LB[dd:a\:b])`).val, "dd:a:b", "escaped colon")
+	assert.Equal(t, lexValue("(;C[soft\\\nbreak])").val, "softbreak", "soft line break")
+	assert.Equal(t, lexValue("(;C[soft\\\r\nbreak])").val, "softbreak", "soft line break")
+
+	assert.Equal(t, lexValue("(;C[caf\xe9 \\] x])").val, "caf\xe9 ] x", "bytes that aren't UTF-8 changed")
+	assert.Equal(t, lexValue("(;C[\\\xe9t\xe9])").val, "\xe9t\xe9", "escaped byte that isn't UTF-8 changed")
+
+	assert.Equal(t, lexValue(`(;C[trailing\`).typ, itemError, "escape at end of input")
+}
+
+func TestLexerSnapshot(t *testing.T) {
+	input := "(;GM[1]PB[Alice];B[pd]C[x\\]y](;W[dp])(;W[dd]))"
+
+	var tokens []Token
+	for lx := NewLexer(input); ; {
+		token := lx.Next()
+		tokens = append(tokens, token)
+		if token.Kind == "EOF" || token.Kind == "error" {
+			break
+		}
+	}
+	assert.Equal(t, tokens[len(tokens)-1].Kind, "EOF", "unexpected error")
+
+	for n := 0; n < len(tokens); n++ {
+		lx := NewLexer(input)
+		for i := 0; i < n; i++ {
+			lx.Next()
+		}
+		resumed := RestoreLexer(input, lx.Snapshot())
+		for _, want := range tokens[n:] {
+			assert.Equal(t, resumed.Next(), want, "resumed lexer gave a different token")
+		}
+	}
+}
+
+func TestLexResyncsAfterErrors(t *testing.T) {
+	l := lexResyncing("(;B[aa]C junk;W[bb]]x(;B[cc]))")
+	defer l.close()
+
+	var values []string
+	errors := 0
+	for {
+		i := l.nextItem()
+		if i.typ == itemEOF {
+			break
+		}
+		switch i.typ {
+		case itemError:
+			errors += 1
+		case itemPropertyValue:
+			values = append(values, i.val)
+		}
+	}
+	assert.Equal(t, errors, 2, "wrong number of errors")
+	assert.Equal(t, values, []string{"aa", "bb", "cc"}, "values after the errors lost")
+}
+
+func TestLexErrorPositions(t *testing.T) {
+	examples := []example{
+		{"(;GM[1]\n;B[aa]\n;W[bb", "line 3, col 6: right bracket ']' expected"},
+		{"(;GM[1]\r\n;B[aa]\r\n\r\n  ;W[bb]]", "line 4, col 9: property or node or parenthesis expected, found ']'"},
+		{"(;GM[1]\r;B[aa]\r;W", "line 3, col 3: left bracket '[' expected here"},
+	}
+	for _, e := range examples {
+		l := lex(e.example)
+		var i item
+		for {
+			i = l.nextItem()
+			if i.typ == itemEOF || i.typ == itemError {
+				break
+			}
+		}
+		assert.Equal(t, i.typ, itemError, "expected an error")
+		assert.Equal(t, strings.HasPrefix(i.val, e.message), true, "wrong error: "+i.val)
+		l.close()
+	}
+}
+
+func TestLexMovesOnly(t *testing.T) {
+	l := lexWithOptions("(;GM[1]C[root];B[aa]C[a \\] comment]TR[aa][bb](;W[bb]LB[bb:A])(;W[cc]AB[dd]))", LexOptions{MovesOnly: true})
+	defer l.close()
+
+	var tokens []string
+	for {
+		i := l.nextItem()
+		if i.typ == itemEOF || i.typ == itemError {
+			assert.Equal(t, i.typ, itemEOF, "unexpected error")
+			break
+		}
+		if i.typ == itemPropertyValue {
+			tokens = append(tokens, "["+i.val+"]")
+		} else {
+			tokens = append(tokens, i.val)
+		}
+	}
+	assert.Equal(t, strings.Join(tokens, ""), "(;GM[1]C[root];B[aa](;W[bb])(;W[cc]AB[dd]))", "wrong tokens kept")
+}
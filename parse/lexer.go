@@ -0,0 +1,124 @@
+package parse
+
+// LexState names the lexer state that scans the next token, so that
+// lexing can be resumed from a snapshot.
+type LexState int
+
+const (
+	LexBegin LexState = iota
+	LexLeftParen
+	LexRightParen
+	LexSemiColon
+	LexPropertyName
+	LexLeftBracket
+)
+
+var lexStates = map[LexState]stateFn{
+	LexBegin:        lexBegin,
+	LexLeftParen:    lexLeftParen,
+	LexRightParen:   lexRightParen,
+	LexSemiColon:    lexSemiColon,
+	LexPropertyName: lexPropertyName,
+	LexLeftBracket:  lexLeftBracket,
+}
+
+// LexerState is the point a Lexer has reached: the byte offset where its
+// next token starts and the state that scans it.
+type LexerState struct {
+	Pos   int
+	State LexState
+}
+
+// Token is a lexed item. Kind is one of "(", ")", ";", "name", "value",
+// "EOF" or "error".
+type Token struct {
+	Kind  string
+	Pos   int
+	Value string
+}
+
+var tokenKinds = map[itemType]string{
+	itemEOF:           "EOF",
+	itemError:         "error",
+	itemLeftParen:     "(",
+	itemRightParen:    ")",
+	itemSemiColon:     ";",
+	itemPropertyName:  "name",
+	itemPropertyValue: "value",
+}
+
+// Lexer tokenizes SGF text, and can snapshot its progress so that an
+// editor can re-lex from the last unchanged point after an edit.
+type Lexer struct {
+	l      *lexer
+	peeked *item
+}
+
+// LexOptions adjusts what the lexer passes on.
+type LexOptions struct {
+	// MovesOnly leaves out every property below the root except moves
+	// and setup stones, while keeping the nodes and tree structure, so
+	// that their values are never allocated.
+	MovesOnly bool
+}
+
+func NewLexer(input string) *Lexer {
+	return &Lexer{l: lex(input)}
+}
+
+// NewLexerWithOptions tokenizes input as opts asks.
+func NewLexerWithOptions(input string, opts LexOptions) *Lexer {
+	return &Lexer{l: lexWithOptions(input, opts)}
+}
+
+// RestoreLexer resumes lexing input from a snapshot.
+func RestoreLexer(input string, s LexerState) *Lexer {
+	state, ok := lexStates[s.State]
+	if !ok {
+		state = lexBegin
+	}
+	return &Lexer{l: lexFrom(input, Pos(s.Pos), state)}
+}
+
+// Close stops the lexer. It must be called if the lexer is abandoned
+// before it returns an EOF or error token.
+func (lx *Lexer) Close() {
+	lx.l.close()
+}
+
+func (lx *Lexer) peek() item {
+	if lx.peeked == nil {
+		i := lx.l.nextItem()
+		lx.peeked = &i
+	}
+	return *lx.peeked
+}
+
+// Next returns the next token.
+func (lx *Lexer) Next() Token {
+	i := lx.peek()
+	if i.typ != itemEOF && i.typ != itemError {
+		lx.peeked = nil
+	}
+	return Token{tokenKinds[i.typ], int(i.pos), i.val}
+}
+
+// Snapshot records where the next token starts. After an error there is
+// nothing to resume, and the snapshot scans on for the next game.
+func (lx *Lexer) Snapshot() LexerState {
+	i := lx.peek()
+	switch i.typ {
+	case itemLeftParen:
+		return LexerState{int(i.pos), LexLeftParen}
+	case itemRightParen:
+		return LexerState{int(i.pos), LexRightParen}
+	case itemSemiColon:
+		return LexerState{int(i.pos), LexSemiColon}
+	case itemPropertyName:
+		return LexerState{int(i.pos), LexPropertyName}
+	case itemPropertyValue:
+		// the value starts just inside its '['
+		return LexerState{int(i.pos) - 1, LexLeftBracket}
+	}
+	return LexerState{int(i.pos), LexBegin}
+}
@@ -3,12 +3,20 @@ package parse
 import (
 	"fmt"
 	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/dhodges/sgfinfo/sgf"
 )
 
 func ParseString(str string) (games []*sgf.Game, err error) {
 	games = Parse(str)
+	if len(games) == 0 {
+		return nil, errors.New("problems parsing sgf: no game found")
+	}
 	if len(games[0].Errors) > 0 {
 		return nil, errors.New(fmt.Sprintf("problems parsing sgf: %q", games[0].Errors[0]))
 	}
@@ -16,14 +24,129 @@ func ParseString(str string) (games []*sgf.Game, err error) {
 	return games, nil
 }
 
+// ParseCollection parses every game tree in input, reporting the first
+// game that has errors. A collection with no games is not an error.
+func ParseCollection(input string) ([]*sgf.Game, error) {
+	games := Parse(input)
+	for n, game := range games {
+		if len(game.Errors) > 0 {
+			return nil, errors.New(fmt.Sprintf("problems parsing sgf: game %d: %q", n+1, game.Errors[0]))
+		}
+	}
+	return games, nil
+}
+
+// ParseReader reads all of r and parses it as ParseString does. An error
+// reading r is returned as it is.
+func ParseReader(r io.Reader) (games []*sgf.Game, err error) {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(string(bytes))
+}
+
+// ParseFile parses the file at fpath, giving each game the file's
+// modification time. Parse errors are prefixed with fpath.
+func ParseFile(fpath string) (games []*sgf.Game, err error) {
+	file, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err = ParseString(string(bytes))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%s: %s", fpath, err))
+	}
+
+	for _, game := range games {
+		game.SetFileModTime(fileInfo.ModTime())
+	}
+	return games, nil
+}
+
 func Parse(input string) (games []*sgf.Game) {
+	return ParseWithConfig(input, ParserConfig{})
+}
+
+// ParseBestEffort parses leniently, carrying on past errors, and returns
+// whatever could be read of the first game, never failing. Parse errors are
+// kept as warnings on the game; input with no game at all gives an empty
+// one.
+func ParseBestEffort(input string) *sgf.Game {
+	games := ParseWithConfig(input, ParserConfig{Lenient: true, TrimSimpleText: true, RecoverErrors: true})
+	if len(games) == 0 {
+		return &sgf.Game{GameInfo: make(sgf.GameInfo)}
+	}
+	game := games[0]
+	game.Warnings = append(game.Warnings, game.Errors...)
+	game.Errors = nil
+	return game
+}
+
+func ParseWithConfig(input string, config ParserConfig) (games []*sgf.Game) {
+	input, decodedFrom := decodeInput(input)
+	return parseDecoded(input, 0, decodedFrom, config)
+}
+
+// parseDecoded parses input from pos on, once decodeInput has converted it
+// from the charset decodedFrom, if any. Errors give positions in the whole
+// of input.
+func parseDecoded(input string, pos Pos, decodedFrom string, config ParserConfig) (games []*sgf.Game) {
 	var currentNode *sgf.Node
 	var game *sgf.Game
-	l := lex(input)
+	l := newLexer(input, pos, lexBegin)
+	l.resync = config.RecoverErrors
+	l.opts = config.LexOptions
+	go l.run()
+	defer l.close()
 	prop := sgf.Property{}
+	valueCount := 0
 	parsingSetup := false
 	parsingGametree := false
 	nodeStack := new(Stack)
+	var variationStart *sgf.Node
+	var outerGames []nestedGame
+	maxErrors := config.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxErrors
+	}
+	errorCount := 0
+	// addError records msg on the game and reports whether parsing should
+	// stop there
+	addError := func(msg string) bool {
+		game.AddError(msg)
+		if !config.RecoverErrors {
+			return true
+		}
+		errorCount += 1
+		if errorCount >= maxErrors {
+			game.AddError(fmt.Sprintf("too many errors, stopped after %d", maxErrors))
+			return true
+		}
+		return false
+	}
+	// stray reports nodes or properties after a game has ended, once for
+	// each run of them
+	strayed := false
+	stray := func(i item) bool {
+		if strayed {
+			return false
+		}
+		strayed = true
+		return addError(l.quoteErrorAt(i.pos, "node or property outside a game tree"))
+	}
 
 Loop:
 	for {
@@ -35,32 +158,54 @@ Loop:
 				game.GameInfo = make(sgf.GameInfo)
 				games = append(games, game)
 				parsingSetup = true
-			} else if parsingSetup {
-				game.AddError(l.QuoteErrorContext("unexpected left parenthesis"))
-				break Loop
-			} else {
-				if len(game.GameInfo) == 0 {
-					parsingSetup = true
-				} else {
-					nodeStack.Push(currentNode)
-					currentNode = currentNode.NewVariation()
-					if l.nextItem().typ != itemSemiColon {
-						game.AddError(l.QuoteErrorContext("semi-colon expected here"))
-						break Loop
-					}
+				strayed = false
+				continue
+			}
+			if parsingSetup {
+				// variations straight after the root hang from an empty
+				// first node
+				parsingSetup = false
+				parsingGametree = true
+				game.GameTree = new(sgf.Node)
+				currentNode = game.GameTree
+			}
+			nodeStack.Push(currentNode)
+			currentNode = currentNode.NewVariation()
+			variationStart = currentNode
+			if next := l.nextItem(); next.typ != itemSemiColon {
+				msg := next.val
+				if next.typ != itemError {
+					msg = l.quoteErrorAt(next.pos, "semi-colon expected here")
+				}
+				if addError(msg) {
+					break Loop
 				}
 			}
 		case itemRightParen:
 			node := nodeStack.Pop()
 			if node != nil {
 				currentNode = node.(*sgf.Node)
+			} else if len(outerGames) > 0 {
+				// end of a nested game: carry on with the game it was in
+				outer := outerGames[len(outerGames)-1]
+				outerGames = outerGames[:len(outerGames)-1]
+				game, nodeStack = outer.game, outer.nodeStack
+				currentNode = nodeStack.Pop().(*sgf.Node)
+				parsingSetup = false
+				parsingGametree = true
 			} else {
 				parsingSetup = false
 				parsingGametree = false
 			}
 		case itemSemiColon:
+			if !parsingSetup && !parsingGametree {
+				if stray(i) {
+					break Loop
+				}
+				continue
+			}
 			if parsingSetup {
-				if len(game.GameInfo) > 0 {
+				if game.HasRoot() {
 					parsingSetup = false
 					parsingGametree = true
 					game.GameTree = new(sgf.Node)
@@ -70,21 +215,117 @@ Loop:
 				currentNode = currentNode.NewNode()
 			}
 		case itemPropertyName:
+			if !parsingSetup && !parsingGametree {
+				if stray(i) {
+					break Loop
+				}
+				continue
+			}
+			if config.Lenient && currentNode != nil && currentNode == variationStart &&
+				currentNode.Point.Name == "" && len(currentNode.Properties) == 0 && isGameInfoProperty(i.val) {
+				variationStart = nil
+				if config.SplitNestedGames {
+					game.AddWarning("nested game split out as a separate game")
+					parent := nodeStack.Peek().(*sgf.Node)
+					parent.Variations = parent.Variations[:len(parent.Variations)-1]
+					outerGames = append(outerGames, nestedGame{game, nodeStack})
+
+					game = new(sgf.Game)
+					game.GameInfo = make(sgf.GameInfo)
+					games = append(games, game)
+					nodeStack = new(Stack)
+					currentNode = nil
+					parsingSetup = true
+					parsingGametree = false
+				} else {
+					game.AddWarning("nested game kept as a variation")
+				}
+			}
 			prop = sgf.Property{Name: i.val, Value: ""}
+			valueCount = 0
 		case itemPropertyValue:
-			prop.Value = i.val
+			if !parsingSetup && !parsingGametree {
+				continue
+			}
+			value := i.val
+			if config.Lenient && !utf8.ValidString(value) && isUTF8Charset(game.GameInfo[sgf.Charset]) {
+				if repaired, ok := repairCP1252(value); ok {
+					value = repaired
+					game.AddWarning(fmt.Sprintf("property %s: repaired Windows-1252 text", prop.Name))
+				}
+			}
+			valueCount += 1
+			if valueCount > 1 && config.Lenient && isTextProperty(prop.Name) {
+				if valueCount == 2 {
+					game.AddWarning(fmt.Sprintf("property %s: joined text split across brackets", prop.Name))
+				}
+				value = prop.Value + value
+				if !parsingSetup {
+					currentNode.Properties = currentNode.Properties[:len(currentNode.Properties)-1]
+				}
+			}
+			if parsingSetup && config.TrimSimpleText && isSimpleTextProperty(prop.Name) {
+				value = strings.TrimSpace(value)
+			}
+			prop.Value = value
+			if config.PointCodec != nil {
+				prop = prop.Recode(config.PointCodec, sgf.LetterCodec{})
+			}
+			added := prop
+			if game.GameInfo[sgf.FileFormat] == "3" {
+				added = ff3Property(prop, valueCount-1)
+				if ff3Removed[prop.Name] && valueCount == 1 {
+					game.AddWarning(fmt.Sprintf("property %s: FF[3] only, with no FF[4] equivalent", prop.Name))
+				}
+			}
 			if parsingSetup {
-				game.AddInfo(prop)
+				game.AddInfo(added)
 			} else {
-				currentNode.AddProperty(prop)
+				currentNode.AddProperty(added)
 			}
 		case itemError:
-			game.AddError(i.val)
-			break Loop
+			if addError(i.val) {
+				break Loop
+			}
 		case itemEOF:
 			break Loop
 		}
 	}
 
+	for _, game := range games {
+		decodeCharset(game, decodedFrom)
+	}
 	return
 }
+
+// nestedGame is the parsing state of a game set aside while a game nested
+// inside it is read.
+type nestedGame struct {
+	game      *sgf.Game
+	nodeStack *Stack
+}
+
+// isGameInfoProperty reports whether name belongs only in a root node.
+func isGameInfoProperty(name string) bool {
+	spec, ok := sgf.PropertyInfo(name)
+	return ok && (spec.NodeType == sgf.RootNode || spec.NodeType == sgf.GameInfoNode)
+}
+
+// isTextProperty reports whether name holds free text, which may only
+// take a single value.
+func isTextProperty(name string) bool {
+	spec, ok := sgf.PropertyInfo(name)
+	return ok && spec.ValueType == "text"
+}
+
+func isMoveOrSetupProperty(name string) bool {
+	spec, ok := sgf.PropertyInfo(name)
+	return ok && (spec.Group == "move" || spec.NodeType == sgf.SetupNode)
+}
+
+// isSimpleTextProperty reports whether name is a game-info property
+// holding a single line of text.
+func isSimpleTextProperty(name string) bool {
+	spec, ok := sgf.PropertyInfo(name)
+	return ok && spec.NodeType == sgf.GameInfoNode && spec.ValueType == "simpletext"
+}
@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/dhodges/sgfinfo/sgf"
+)
+
+const lastGameWindow = 4096
+
+// ParseLastGameInfo reads the game info of the last game in a collection,
+// reading backwards from the end of r. The read window doubles until it
+// takes in the start of the last game.
+func ParseLastGameInfo(r io.ReaderAt, size int64) (sgf.GameInfo, error) {
+	window := int64(lastGameWindow)
+	for {
+		if window > size {
+			window = size
+		}
+		buf := make([]byte, window)
+		if _, err := r.ReadAt(buf, size-window); err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		tail := string(buf)
+		for start := strings.LastIndex(tail, "(;"); start >= 0; start = strings.LastIndex(tail[:start], "(;") {
+			if !closesAtEnd(tail[start:]) {
+				continue
+			}
+			games := Parse(tail[start:])
+			if len(games) == 1 && len(games[0].Errors) == 0 {
+				return games[0].GameInfo, nil
+			}
+		}
+
+		if window == size {
+			return nil, errors.New("no game found")
+		}
+		window *= 2
+	}
+}
+
+// closesAtEnd reports whether the game tree opening at the start of s is
+// closed by the last parenthesis in s, with only whitespace following.
+func closesAtEnd(s string) bool {
+	trees := splitGameTrees(s)
+	return len(trees) == 1 && trees[0] == span{0, Pos(len(strings.TrimSpace(s)))}
+}
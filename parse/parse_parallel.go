@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dhodges/sgfinfo/sgf"
+)
+
+// ParseCollectionParallel splits input into its top-level game trees and
+// parses them on a pool of workers, giving the same result as
+// ParseCollection. Games are returned in input order.
+func ParseCollectionParallel(input string, workers int) (games []*sgf.Game, err error) {
+	if workers < 1 {
+		workers = 1
+	}
+	// decode first, as the parser does, so that multibyte characters
+	// can't be taken for brackets or parentheses
+	input, decodedFrom := decodeInput(input)
+	trees := splitGameTrees(input)
+	results := make([][]*sgf.Game, len(trees))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tree := trees[i]
+				results[i] = parseDecoded(input[:tree.end], tree.start, decodedFrom, ParserConfig{})
+			}
+		}()
+	}
+	for i := range trees {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range results {
+		games = append(games, result...)
+	}
+	for n, game := range games {
+		if len(game.Errors) > 0 {
+			return nil, errors.New(fmt.Sprintf("problems parsing sgf: game %d: %q", n+1, game.Errors[0]))
+		}
+	}
+	return games, nil
+}
+
+// span is the start and end of a game tree in the input.
+type span struct {
+	start, end Pos
+}
+
+// splitGameTrees returns where each top-level game tree in input lies,
+// skipping over bracketed property values. An unterminated final tree
+// runs to the end of input, for the parser to report.
+func splitGameTrees(input string) (trees []span) {
+	depth, start := 0, 0
+	inValue := false
+	for i := 0; i < len(input); i++ {
+		switch {
+		case inValue && input[i] == '\\':
+			i++
+		case inValue:
+			inValue = input[i] != ']'
+		case input[i] == '[':
+			inValue = true
+		case input[i] == '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case input[i] == ')' && depth > 0:
+			depth--
+			if depth == 0 {
+				trees = append(trees, span{Pos(start), Pos(i + 1)})
+			}
+		}
+	}
+	if depth > 0 {
+		trees = append(trees, span{Pos(start), Pos(len(input))})
+	}
+	return trees
+}
@@ -0,0 +1,369 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Color int
+
+const (
+	Empty Color = iota
+	Black
+	White
+)
+
+func (c Color) Opponent() Color {
+	switch c {
+	case Black:
+		return White
+	case White:
+		return Black
+	}
+	return Empty
+}
+
+type Move struct {
+	Color Color
+	Point Point
+}
+
+type Board struct {
+	Size         int
+	AllowSuicide bool // remove a suicided group rather than reject the move
+	grid         [][]Color
+	captured     []Point // stones removed by the most recent Play
+}
+
+func NewBoard(size int) *Board {
+	grid := make([][]Color, size)
+	for y := range grid {
+		grid[y] = make([]Color, size)
+	}
+	return &Board{Size: size, grid: grid}
+}
+
+func (b *Board) onBoard(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.Size && y < b.Size
+}
+
+func (b *Board) At(p Point) Color {
+	x, y := p.Coords()
+	if !b.onBoard(x, y) {
+		return Empty
+	}
+	return b.grid[y][x]
+}
+
+func (b *Board) set(p Point, c Color) {
+	x, y := p.Coords()
+	b.grid[y][x] = c
+}
+
+func (b *Board) neighbours(p Point) []Point {
+	x, y := p.Coords()
+	var points []Point
+	for _, d := range [][2]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}} {
+		if b.onBoard(x+d[0], y+d[1]) {
+			points = append(points, pointAt(x+d[0], y+d[1]))
+		}
+	}
+	return points
+}
+
+// group returns the stones connected to p along with their liberties.
+func (b *Board) group(p Point) (stones []Point, liberties []Point) {
+	color := b.At(p)
+	seen := map[Point]bool{p: true}
+	libs := map[Point]bool{}
+	todo := []Point{p}
+	for len(todo) > 0 {
+		stone := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		stones = append(stones, stone)
+		for _, n := range b.neighbours(stone) {
+			switch b.At(n) {
+			case Empty:
+				if !libs[n] {
+					libs[n] = true
+					liberties = append(liberties, n)
+				}
+			case color:
+				if !seen[n] {
+					seen[n] = true
+					todo = append(todo, n)
+				}
+			}
+		}
+	}
+	return stones, liberties
+}
+
+// Play places a stone and removes any opponent groups left without
+// liberties, returning the captured points. A move leaving its own group
+// without liberties is an error unless AllowSuicide is set, in which case
+// the group is removed and included in the captured points.
+func (b *Board) Play(m Move) (captured []Point, err error) {
+	x, y := m.Point.Coords()
+	if !b.onBoard(x, y) {
+		return nil, errors.New(fmt.Sprintf("move off board at %s", m.Point))
+	}
+	switch b.At(m.Point) {
+	case Empty:
+	case m.Color:
+		return nil, errors.New(fmt.Sprintf("move on own stone at %s", m.Point))
+	default:
+		return nil, errors.New(fmt.Sprintf("point already occupied at %s", m.Point))
+	}
+
+	b.set(m.Point, m.Color)
+	for _, n := range b.neighbours(m.Point) {
+		if b.At(n) != m.Color.Opponent() {
+			continue
+		}
+		stones, liberties := b.group(n)
+		if len(liberties) == 0 {
+			for _, stone := range stones {
+				b.set(stone, Empty)
+			}
+			captured = append(captured, stones...)
+		}
+	}
+
+	if stones, liberties := b.group(m.Point); len(liberties) == 0 {
+		if !b.AllowSuicide {
+			b.set(m.Point, Empty)
+			return nil, errors.New(fmt.Sprintf("suicide at %s", m.Point))
+		}
+		for _, stone := range stones {
+			b.set(stone, Empty)
+		}
+		captured = append(captured, stones...)
+	}
+
+	b.captured = captured
+	return captured, nil
+}
+
+// KoPoint returns the point at which an immediate recapture of
+// lastMove's single captured stone would be illegal.
+func (b *Board) KoPoint(lastMove Move) (Point, bool) {
+	if len(b.captured) != 1 || b.At(lastMove.Point) != lastMove.Color {
+		return Point{}, false
+	}
+	stones, liberties := b.group(lastMove.Point)
+	if len(stones) != 1 || len(liberties) != 1 || liberties[0] != b.captured[0] {
+		return Point{}, false
+	}
+	return b.captured[0], true
+}
+
+// ToSetup lists the stones on the board by colour, in the form of the AB
+// and AW setup properties.
+func (b *Board) ToSetup() (ab, aw []Point) {
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			switch b.grid[y][x] {
+			case Black:
+				ab = append(ab, pointAt(x, y))
+			case White:
+				aw = append(aw, pointAt(x, y))
+			}
+		}
+	}
+	return ab, aw
+}
+
+// Dame returns the empty points, in row-major order, whose empty region
+// borders stones of both colours and so belongs to neither player.
+func (b *Board) Dame() (dame []Point) {
+	neutral := map[Point]bool{}
+	seen := map[Point]bool{}
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			p := pointAt(x, y)
+			if b.At(p) != Empty || seen[p] {
+				continue
+			}
+			region, borders := b.region(p)
+			for _, q := range region {
+				seen[q] = true
+				neutral[q] = borders[Black] && borders[White]
+			}
+		}
+	}
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			if p := pointAt(x, y); neutral[p] {
+				dame = append(dame, p)
+			}
+		}
+	}
+	return dame
+}
+
+// region returns the empty points connected to p, and the colours of the
+// stones bordering them.
+func (b *Board) region(p Point) (points []Point, borders map[Color]bool) {
+	borders = map[Color]bool{}
+	seen := map[Point]bool{p: true}
+	todo := []Point{p}
+	for len(todo) > 0 {
+		point := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		points = append(points, point)
+		for _, n := range b.neighbours(point) {
+			if c := b.At(n); c != Empty {
+				borders[c] = true
+			} else if !seen[n] {
+				seen[n] = true
+				todo = append(todo, n)
+			}
+		}
+	}
+	return points, borders
+}
+
+// BoundingBox returns the smallest rectangle, in 0-based coordinates,
+// holding every stone on the board. empty is true when there are none.
+func (b *Board) BoundingBox() (x0, y0, x1, y1 int, empty bool) {
+	x0, y0, x1, y1 = b.Size, b.Size, -1, -1
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			if b.grid[y][x] == Empty {
+				continue
+			}
+			if x < x0 {
+				x0 = x
+			}
+			if x > x1 {
+				x1 = x
+			}
+			if y < y0 {
+				y0 = y
+			}
+			y1 = y
+		}
+	}
+	if x1 < 0 {
+		return 0, 0, 0, 0, true
+	}
+	return x0, y0, x1, y1, false
+}
+
+// Occupancy returns the fraction of the board's points holding a stone.
+func (b *Board) Occupancy() float64 {
+	stones := 0
+	for _, row := range b.grid {
+		for _, c := range row {
+			if c != Empty {
+				stones++
+			}
+		}
+	}
+	return float64(stones) / float64(b.Size*b.Size)
+}
+
+// setupProperties gives the position as AB and AW properties.
+func (b *Board) setupProperties() (props []Property) {
+	ab, aw := b.ToSetup()
+	for _, p := range ab {
+		props = append(props, Property{"AB", p.SGF()})
+	}
+	for _, p := range aw {
+		props = append(props, Property{"AW", p.SGF()})
+	}
+	return props
+}
+
+func (b *Board) String() string {
+	str := ""
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			switch b.grid[y][x] {
+			case Black:
+				str += "X"
+			case White:
+				str += "O"
+			default:
+				str += "."
+			}
+		}
+		str += "\n"
+	}
+	return str
+}
+
+// UnicodeString draws the board with box-drawing characters, stones as
+// ● and ○ and the star points as ╋.
+func (b *Board) UnicodeString() string {
+	last := b.Size - 1
+	str := ""
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			if x > 0 {
+				str += "─"
+			}
+			switch b.grid[y][x] {
+			case Black:
+				str += "●"
+			case White:
+				str += "○"
+			default:
+				str += b.intersection(x, y, last)
+			}
+		}
+		str += "\n"
+	}
+	return str
+}
+
+func (b *Board) intersection(x, y, last int) string {
+	if b.isStarPoint(x, y) {
+		return "╋"
+	}
+	row := "├┼┤"
+	switch y {
+	case 0:
+		row = "┌┬┐"
+	case last:
+		row = "└┴┘"
+	}
+	runes := []rune(row)
+	switch x {
+	case 0:
+		return string(runes[0])
+	case last:
+		return string(runes[2])
+	}
+	return string(runes[1])
+}
+
+// isStarPoint reports whether x, y is a star point: the 4-4 points (3-3 on
+// boards under 13x13) and the centre, with the side points too on boards
+// of 15x15 and up.
+func (b *Board) isStarPoint(x, y int) bool {
+	if b.Size < 7 {
+		return false
+	}
+	edge := 3
+	if b.Size < 13 {
+		edge = 2
+	}
+	mid := -1
+	if b.Size%2 == 1 {
+		mid = b.Size / 2
+	}
+	line := func(i int) (corner, centre bool) {
+		return i == edge || i == b.Size-1-edge, i == mid
+	}
+	xCorner, xMid := line(x)
+	yCorner, yMid := line(y)
+	switch {
+	case xCorner && yCorner, xMid && yMid:
+		return true
+	case b.Size >= 15:
+		return (xCorner && yMid) || (xMid && yCorner)
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package sgf
+
+// FirstCapture replays the main line and returns the first move that
+// captures any stones, along with the points captured.
+func (sgf *Game) FirstCapture() (moveNum int, captured []Point, ok bool) {
+	sgf.replay(func(n int, node *Node, board *Board) bool {
+		if len(board.captured) > 0 {
+			moveNum, captured, ok = n, board.captured, true
+			return false
+		}
+		return true
+	})
+	return moveNum, captured, ok
+}
+
+// OccupiedPoints replays the main line and counts the moves played on
+// each point. A point that is captured and played again counts twice.
+func (sgf *Game) OccupiedPoints() map[Point]int {
+	counts := map[Point]int{}
+	sgf.replay(func(n int, node *Node, board *Board) bool {
+		if m, ok := node.move(board.Size); ok {
+			counts[m.Point] += 1
+		}
+		return true
+	})
+	return counts
+}
+
+// NumberedBoard replays the first upTo moves of the main line and returns
+// the position along with the number of the move that placed each stone
+// still on the board, for drawing numbered diagrams. A point played again
+// after a capture takes the later number.
+func (sgf *Game) NumberedBoard(upTo int) (*Board, map[Point]int) {
+	numbers := map[Point]int{}
+	if upTo < 1 {
+		board, _ := sgf.replayLine(nil, nil)
+		return board, numbers
+	}
+	board, _ := sgf.replay(func(n int, node *Node, board *Board) bool {
+		for _, p := range board.captured {
+			delete(numbers, p)
+		}
+		if m, ok := node.move(board.Size); ok && board.At(m.Point) == m.Color {
+			numbers[m.Point] = n
+		}
+		return n < upTo
+	})
+	return board, numbers
+}
+
+type PointEventKind int
+
+const (
+	Placed PointEventKind = iota
+	Cleared
+	Captured
+)
+
+// PointEvent is a change to a single point: a stone placed on it, cleared
+// from it by AE, or captured. MoveNumber is the number of the move made or
+// the number of moves played before the change.
+type PointEvent struct {
+	MoveNumber int
+	Kind       PointEventKind
+	Color      Color
+}
+
+// PointHistory replays the main line and lists, in order, every change to
+// the stone on p.
+func (sgf *Game) PointHistory(p Point) (events []PointEvent) {
+	clears := func(props []Property) bool {
+		for _, prop := range props {
+			if prop.Name != "AE" {
+				continue
+			}
+			for _, q := range pointList(prop.Value) {
+				if q == p {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if clears(sgf.Setup) {
+		events = append(events, PointEvent{0, Cleared, Empty})
+	}
+
+	// replay only visits moves, so AE on the nodes between them is
+	// picked up as each move is reached
+	line := sgf.mainLine()
+	next, moveNum := 0, 0
+	clearedUpTo := func(last *Node) {
+		for ; next < len(line); next++ {
+			if clears(line[next].Properties) {
+				events = append(events, PointEvent{moveNum, Cleared, Empty})
+			}
+			if line[next] == last {
+				next++
+				return
+			}
+		}
+	}
+
+	sgf.replay(func(n int, node *Node, board *Board) bool {
+		clearedUpTo(node)
+		moveNum = n
+		m, ok := node.move(board.Size)
+		if !ok {
+			return true
+		}
+		if m.Point == p {
+			events = append(events, PointEvent{n, Placed, m.Color})
+		}
+		color := m.Color.Opponent()
+		for _, q := range board.captured {
+			if q == m.Point {
+				// suicide: the mover's own stones were removed
+				color = m.Color
+			}
+		}
+		for _, q := range board.captured {
+			if q == p {
+				events = append(events, PointEvent{n, Captured, color})
+			}
+		}
+		return true
+	})
+	clearedUpTo(nil)
+	return events
+}
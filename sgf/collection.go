@@ -0,0 +1,110 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Collection is the games of a single SGF file, in order.
+type Collection []*Game
+
+// ApplyDefaults fills in game-info properties each game is missing from
+// defaults, for collections that record shared values only once.
+func (c Collection) ApplyDefaults(defaults GameInfo) {
+	for _, game := range c {
+		game.MergeInfo(defaults, false)
+	}
+}
+
+// DiffGames compares the games of two collections by MoveHash, returning
+// the indices of the games in c that are not in other, and of those in
+// other that are not in c.
+func (c Collection) DiffGames(other Collection) (onlyInA, onlyInB []int) {
+	hashes := func(games Collection) map[uint64]bool {
+		seen := map[uint64]bool{}
+		for _, game := range games {
+			seen[game.MoveHash()] = true
+		}
+		return seen
+	}
+	inA, inB := hashes(c), hashes(other)
+	for i, game := range c {
+		if !inB[game.MoveHash()] {
+			onlyInA = append(onlyInA, i)
+		}
+	}
+	for i, game := range other {
+		if !inA[game.MoveHash()] {
+			onlyInB = append(onlyInB, i)
+		}
+	}
+	return onlyInA, onlyInB
+}
+
+// WriteCollection writes each game to its own .sgf file in dir, creating
+// dir if needed. nameFn gives the file name for the game at index i; if it
+// is nil, games are named PB-vs-PW-DT, or by index when the players are
+// unknown. Characters not allowed in file names are replaced with "_", and
+// a name already taken by an earlier game gets a number added, so that no
+// game overwrites another. Every game is attempted, and failures are
+// reported together.
+func WriteCollection(dir string, games []*Game, nameFn func(*Game, int) string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if nameFn == nil {
+		nameFn = defaultGameName
+	}
+
+	var failures []string
+	taken := map[string]bool{}
+	for i, game := range games {
+		base := sanitizeFileName(strings.TrimSuffix(nameFn(game, i), ".sgf"))
+		if base == "" {
+			base = fmt.Sprintf("%d", i+1)
+		}
+		name := base + ".sgf"
+		for n := 2; taken[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d.sgf", base, n)
+		}
+		taken[strings.ToLower(name)] = true
+
+		fpath := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(fpath, []byte(game.String()), 0644); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(fmt.Sprintf("problems writing collection: %s", strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+func defaultGameName(sgf *Game, i int) string {
+	black, white := sgf.GameInfo[PlayerBlackName], sgf.GameInfo[PlayerWhiteName]
+	if black == "" || white == "" {
+		return fmt.Sprintf("%d", i+1)
+	}
+	name := black + "-vs-" + white
+	if date := sgf.GameInfo[Date]; date != "" {
+		name += "-" + date
+	}
+	return name
+}
+
+// sanitizeFileName replaces the characters that some file system doesn't
+// allow in a name, or that would make it a path, with "_". Leading and
+// trailing spaces and dots are dropped, so the name can't be "." or "..".
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return strings.Trim(name, " .")
+}
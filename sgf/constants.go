@@ -26,3 +26,6 @@ const User = "US"
 const Charset = "CA"
 const Boardsize = "SZ"
 const Komi = "KM"
+const NodeName = "N"
+const FileFormat = "FF"
+const GameType = "GM"
@@ -0,0 +1,63 @@
+package sgf
+
+// TOCEntry locates a named node. Path holds the index of the child taken
+// at each branch point on the way to the node.
+type TOCEntry struct {
+	Path       []int
+	Name       string
+	MoveNumber int
+}
+
+// TableOfContents lists every node carrying a node name (N), in tree
+// order, with the move number it is reached at.
+func (sgf *Game) TableOfContents() (entries []TOCEntry) {
+	if name, ok := sgf.GetInfo(NodeName); ok {
+		entries = append(entries, TOCEntry{nil, name, 0})
+	}
+
+	var walk func(node *Node, path []int, moveNum int)
+	walk = func(node *Node, path []int, moveNum int) {
+		if node.Point.Name != "" {
+			moveNum += 1
+		}
+		if name, ok := node.GetProperty(NodeName); ok {
+			entries = append(entries, TOCEntry{append([]int{}, path...), name, moveNum})
+		}
+		children := node.children()
+		if len(children) == 1 {
+			walk(children[0], path, moveNum)
+			return
+		}
+		for i, child := range children {
+			walk(child, append(path[:len(path):len(path)], i), moveNum)
+		}
+	}
+	if sgf.GameTree != nil {
+		walk(sgf.GameTree, nil, 0)
+	}
+	return entries
+}
+
+// CommentEntry is a comment and the main-line move number it follows.
+type CommentEntry struct {
+	MoveNumber int
+	Comment    string
+}
+
+// CommentTimeline lists the comments on the main line in order. A comment
+// on the root is given move 0.
+func (sgf *Game) CommentTimeline() (timeline []CommentEntry) {
+	if comment, ok := sgf.GetInfo(Comment); ok {
+		timeline = append(timeline, CommentEntry{0, comment})
+	}
+	moveNum := 0
+	for _, node := range sgf.mainLine() {
+		if node.Point.Name != "" {
+			moveNum += 1
+		}
+		if comment, ok := node.GetProperty(Comment); ok {
+			timeline = append(timeline, CommentEntry{moveNum, comment})
+		}
+	}
+	return timeline
+}
@@ -0,0 +1,83 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DetachVariation removes the i-th variation at node and returns it as a
+// game of its own, with the original game info and the position at the
+// branch point as its setup.
+func (sgf *Game) DetachVariation(node *Node, i int) (*Game, error) {
+	if i < 0 || i >= len(node.Variations) {
+		return nil, errors.New(fmt.Sprintf("no variation %d (variation count: %d)", i, len(node.Variations)))
+	}
+	line := sgf.lineTo(node)
+	if line == nil {
+		return nil, errors.New("node not found in game tree")
+	}
+	board, err := sgf.replayLine(line, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	game := new(Game)
+	game.GameInfo = GameInfo(sgf.GameInfo.clone())
+	game.Setup = board.setupProperties()
+	game.GameTree = node.Variations[i]
+
+	node.Variations = append(node.Variations[:i:i], node.Variations[i+1:]...)
+	return game, nil
+}
+
+// Chapters splits the main line into games of size moves each. Every
+// chapter after the first starts from the position the one before it
+// ended on, given as setup stones. Chapters are copies, so changing one
+// leaves the game alone.
+func (sgf *Game) Chapters(size int) (chapters []*Game) {
+	if size < 1 {
+		return nil
+	}
+	line := sgf.mainLine()
+
+	// each chapter runs from the node of its first move to the next
+	// chapter's start
+	starts := []int{0}
+	moves := 0
+	for i, node := range line {
+		if node.Point.Name == "" {
+			continue
+		}
+		if moves > 0 && moves%size == 0 {
+			starts = append(starts, i)
+		}
+		moves += 1
+	}
+
+	for c, start := range starts {
+		end := len(line)
+		if c+1 < len(starts) {
+			end = starts[c+1]
+		}
+		chapter := new(Game)
+		chapter.GameInfo = GameInfo(sgf.GameInfo.clone())
+		if c == 0 {
+			chapter.Setup = append([]Property(nil), sgf.Setup...)
+		} else {
+			board, _ := sgf.replayLine(line[:start], nil)
+			chapter.Setup = board.setupProperties()
+		}
+		var last *Node
+		for _, node := range line[start:end] {
+			copied := &Node{Point: node.Point, Properties: append([]Property(nil), node.Properties...)}
+			if last == nil {
+				chapter.GameTree = copied
+			} else {
+				last.Next = copied
+			}
+			last = copied
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters
+}
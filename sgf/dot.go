@@ -0,0 +1,36 @@
+package sgf
+
+import (
+	"fmt"
+	"io"
+)
+
+// ToDOT writes the game tree as a Graphviz digraph, one vertex per node
+// labelled with its move, and an edge from each node to every child.
+func (sgf *Game) ToDOT(w io.Writer) error {
+	str := "digraph game {\n"
+	str += "\tn0 [label=\"root\"];\n"
+
+	count := 1
+	var walk func(parent int, node *Node)
+	walk = func(parent int, node *Node) {
+		id := count
+		count += 1
+		label := "-"
+		if node.Point.Name != "" {
+			label = node.Point.String()
+		}
+		str += fmt.Sprintf("\tn%d [label=%q];\n", id, label)
+		str += fmt.Sprintf("\tn%d -> n%d;\n", parent, id)
+		for _, child := range node.children() {
+			walk(id, child)
+		}
+	}
+	if sgf.GameTree != nil {
+		walk(0, sgf.GameTree)
+	}
+
+	str += "}\n"
+	_, err := io.WriteString(w, str)
+	return err
+}
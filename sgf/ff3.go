@@ -0,0 +1,99 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dhodges/sgfinfo/util"
+)
+
+// ff3Dropped lists FF[4] properties with no FF[3] equivalent.
+var ff3Dropped = map[string]bool{
+	"AP": true, "AR": true, "CA": true, "DD": true, "LN": true, "PM": true, "ST": true,
+}
+
+// ff3Renamed maps FF[4] properties onto the FF[3] names they replaced.
+var ff3Renamed = map[string]string{
+	"MA": "M",
+}
+
+func isPointListProperty(name string) bool {
+	switch name {
+	case "AB", "AW", "AE", "CR", "MA", "SL", "SQ", "TB", "TR", "TW", "VW":
+		return true
+	}
+	return false
+}
+
+// WriteFF3 writes the game for FF[3] readers: passes are written as tt on
+// boards up to 19x19, compressed point lists are expanded, and renamed
+// properties get their old names. Properties FF[3] cannot represent are
+// left out; FF3Warnings lists them. The game itself is left unchanged.
+func (sgf *Game) WriteFF3(w io.Writer) error {
+	game, _ := sgf.ff3()
+	_, err := io.WriteString(w, game.Serialize(SerializeOptions{PassAsTT: true}))
+	return err
+}
+
+// FF3Warnings returns a warning for each property WriteFF3 would leave out
+// because FF[3] cannot represent it.
+func (sgf *Game) FF3Warnings() []error {
+	_, warnings := sgf.ff3()
+	return warnings
+}
+
+// ff3 converts the game for WriteFF3, with a warning for each property it
+// drops.
+func (sgf *Game) ff3() (game Game, warnings []error) {
+	convert := func(prop Property) []Property {
+		if ff3Dropped[prop.Name] {
+			warnings = append(warnings, errors.New(fmt.Sprintf("%s: not representable in FF[3]", prop)))
+			return nil
+		}
+		props := []Property{prop}
+		if points := pointList(prop.Value); isPointListProperty(prop.Name) && len(points) > 0 {
+			props = nil
+			for _, p := range points {
+				props = append(props, Property{prop.Name, p.SGF()})
+			}
+		}
+		if name, ok := ff3Renamed[prop.Name]; ok {
+			for i := range props {
+				props[i].Name = name
+			}
+		}
+		return props
+	}
+
+	var convertNode func(node *Node) *Node
+	convertNode = func(node *Node) *Node {
+		if node == nil {
+			return nil
+		}
+		converted := &Node{Next: convertNode(node.Next)}
+		if node.Point.Name != "" {
+			converted.Point = convert(node.Point)[0]
+		}
+		for _, prop := range node.Properties {
+			converted.Properties = append(converted.Properties, convert(prop)...)
+		}
+		for _, nodevar := range node.Variations {
+			converted.Variations = append(converted.Variations, convertNode(nodevar))
+		}
+		return converted
+	}
+
+	game = Game{GameInfo: make(GameInfo), GameTree: convertNode(sgf.GameTree)}
+	for _, name := range util.KeysFromMap(sgf.GameInfo) {
+		for _, prop := range convert(Property{name, sgf.GameInfo[name]}) {
+			game.GameInfo[prop.Name] = prop.Value
+		}
+	}
+	game.GameInfo[FileFormat] = "3"
+	for _, prop := range sgf.Setup {
+		game.Setup = append(game.Setup, convert(prop)...)
+	}
+
+	return game, warnings
+}
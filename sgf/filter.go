@@ -0,0 +1,47 @@
+package sgf
+
+import "strings"
+
+// FilterGames returns the games pred matches, in order.
+func FilterGames(games []*Game, pred func(*Game) bool) (filtered []*Game) {
+	for _, game := range games {
+		if pred(game) {
+			filtered = append(filtered, game)
+		}
+	}
+	return filtered
+}
+
+// ByPlayer matches games where name played either colour, ignoring case.
+func ByPlayer(name string) func(*Game) bool {
+	return func(sgf *Game) bool {
+		return strings.EqualFold(sgf.GameInfo[PlayerBlackName], name) ||
+			strings.EqualFold(sgf.GameInfo[PlayerWhiteName], name)
+	}
+}
+
+// ByMinMoves matches games with at least n main-line moves.
+func ByMinMoves(n int) func(*Game) bool {
+	return func(sgf *Game) bool {
+		return len(sgf.moveNodes()) >= n
+	}
+}
+
+// ByBoardSize matches games on a square board of the given size. SZ
+// defaults to 19 when missing, and a rectangular board never matches; use
+// ByBoardDimensions for those.
+func ByBoardSize(size int) func(*Game) bool {
+	return ByBoardDimensions(size, size)
+}
+
+// ByBoardDimensions matches games on a board width points across and
+// height points down. SZ defaults to 19x19 when missing.
+func ByBoardDimensions(width, height int) func(*Game) bool {
+	return func(sgf *Game) bool {
+		w, h, err := sgf.GameInfo.BoardSize()
+		if err != nil && err != ErrPropertyMissing {
+			return false
+		}
+		return w == width && h == height
+	}
+}
@@ -3,54 +3,164 @@ package sgf
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Game struct {
 	GameInfo GameInfo
+	Setup    []Property // setup (AB, AW, AE, PL) and other list properties from the root node
 	GameTree *Node
 	Errors   []error
+	Warnings []error
+	modTime  time.Time
 }
 
 func (sgf *Game) AddInfo(prop Property) {
+	if isSetupProperty(prop.Name) || isListProperty(prop.Name) {
+		sgf.Setup = append(sgf.Setup, prop)
+		return
+	}
 	sgf.GameInfo[strings.ToUpper(prop.Name)] = prop.Value
 }
 
+func isSetupProperty(name string) bool {
+	spec, ok := PropertyInfo(strings.ToUpper(name))
+	return ok && spec.NodeType == SetupNode
+}
+
+// HasRoot reports whether any root node properties have been read.
+func (sgf *Game) HasRoot() bool {
+	return len(sgf.GameInfo) > 0 || len(sgf.Setup) > 0
+}
+
+func (sgf Game) setupString() string {
+	return propertiesString(sgf.Setup)
+}
+
 func (sgf *Game) GetInfo(name string) (value string, ok bool) {
 	value, ok = sgf.GameInfo[strings.ToUpper(name)]
 	return value, ok
 }
 
+// Copyright returns the CP value.
+func (sgf *Game) Copyright() string {
+	return sgf.GameInfo[Copyright]
+}
+
+var timeAmount = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)`)
+
+// MainTimeLenient returns the TM value as a duration along with the raw
+// value. TM should be a number of seconds, but some files give the time
+// with units or as free text, such as "1h30m" or "2 hours each"; every
+// amount with a unit is added up. ok is false if no time could be read.
+func (sgf *Game) MainTimeLenient() (main time.Duration, raw string, ok bool) {
+	raw = sgf.GameInfo[TimeLimits]
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), raw, true
+	}
+	for _, match := range timeAmount.FindAllStringSubmatch(value, -1) {
+		amount, _ := strconv.ParseFloat(match[1], 64)
+		unit := time.Second
+		switch match[2][0] {
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		main += time.Duration(amount * float64(unit))
+		ok = true
+	}
+	return main, raw, ok
+}
+
+// MergeInfo adds the given game-info properties to the root, for metadata
+// kept apart from the game record. Existing values are only replaced when
+// overwrite is set.
+func (sgf *Game) MergeInfo(info map[string]string, overwrite bool) {
+	if sgf.GameInfo == nil {
+		sgf.GameInfo = make(GameInfo)
+	}
+	for name, value := range info {
+		if _, exists := sgf.GetInfo(name); exists && !overwrite {
+			continue
+		}
+		sgf.AddInfo(Property{Name: name, Value: value})
+	}
+}
+
+// holdsRootVariations reports whether the game tree starts with the empty
+// node the parser adds to hold variations that follow straight on from
+// the root, as in "(;GM[1](;B[pd])(;B[dd]))".
+func (sgf Game) holdsRootVariations() bool {
+	node := sgf.GameTree
+	return node != nil && node.Next == nil && node.isEmpty() && len(node.Variations) > 0
+}
+
+// firstNode returns the first node after the root, taking the first
+// variation when the root has several.
+func (sgf Game) firstNode() *Node {
+	if sgf.holdsRootVariations() {
+		return sgf.GameTree.Variations[0]
+	}
+	return sgf.GameTree
+}
+
 func (sgf Game) GameTreeString() string {
 	treeString := ""
-	for node := sgf.GameTree; node != nil; node = node.Next {
+	node := sgf.GameTree
+	if node != nil && node.isEmpty() && len(node.Variations) > 0 {
+		// the empty node holding variations from the root is not written
+		treeString, node = node.variationString(), node.Next
+	}
+	for ; node != nil; node = node.Next {
 		treeString += node.String()
 	}
 	return treeString
 }
 
 func (sgf Game) String() string {
-	return "(" + sgf.GameInfo.String() + sgf.GameTreeString() + ")"
+	return sgf.Serialize(SerializeOptions{})
 }
 
+// NodeCount counts the nodes of the first line of the game tree, the root
+// included, so an empty game "(;)" has one node.
 func (sgf Game) NodeCount() int {
-	count := 0
-	for node := sgf.GameTree; node != nil; node = node.Next {
+	count := 1
+	for node := sgf.firstNode(); node != nil; node = node.Next {
 		count += 1
 	}
 	return count
 }
 
+// IsLinear reports whether the game is a single line of play, with no
+// node offering a choice of continuations.
+func (sgf *Game) IsLinear() bool {
+	for _, node := range sgf.GameTree.subtree() {
+		if len(node.children()) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// NthNode returns the nth node of the first line of the game tree,
+// counting from 1 at the first node after the root. The root is not a
+// Node, its properties being held in GameInfo and Setup, so it is left out
+// of the count and the last node is NthNode(NodeCount()-1).
 func (sgf Game) NthNode(n int) (node *Node, err error) {
 	if n < 1 {
 		return nil, errors.New("n less than 1")
 	}
-	nodeCount := sgf.NodeCount()
+	nodeCount := sgf.NodeCount() - 1
 
 	if n > nodeCount {
 		return nil, errors.New(fmt.Sprintf("n greater than node count (%d)", nodeCount))
 	}
-	for node = sgf.GameTree; n > 1; n -= 1 {
+	for node = sgf.firstNode(); n > 1; n -= 1 {
 		node = node.Next
 	}
 	return node, nil
@@ -59,3 +169,29 @@ func (sgf Game) NthNode(n int) (node *Node, err error) {
 func (sgf *Game) AddError(msg string) {
 	sgf.Errors = append(sgf.Errors, errors.New(msg))
 }
+
+func (sgf *Game) AddWarning(msg string) {
+	sgf.Warnings = append(sgf.Warnings, errors.New(msg))
+}
+
+// FileModTime is the modification time of the file the game was parsed
+// from, or the zero time when it didn't come from a file.
+func (sgf *Game) FileModTime() time.Time {
+	return sgf.modTime
+}
+
+func (sgf *Game) SetFileModTime(t time.Time) {
+	sgf.modTime = t
+}
+
+// TrimEmpty removes trailing nodes that carry neither a move nor any
+// properties. Empty nodes followed by meaningful ones are kept.
+func (sgf *Game) TrimEmpty() {
+	sgf.GameTree = sgf.GameTree.trimEmpty()
+}
+
+// DedupeVariations removes variations identical to another at the same
+// branch point, such as those left behind by merging study files.
+func (sgf *Game) DedupeVariations() {
+	sgf.GameTree.dedupeVariations()
+}
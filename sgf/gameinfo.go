@@ -1,6 +1,9 @@
 package sgf
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"encoding/json"
 
@@ -12,7 +15,7 @@ type GameInfo map[string]string
 func (gi GameInfo) String() string {
 	str := ""
 	for _, k := range util.KeysFromMap(gi) {
-		str += k + "[" + gi[k] + "]"
+		str += k + "[" + escapeValue(gi[k]) + "]"
 	}
 	return ";" + str
 }
@@ -48,6 +51,115 @@ func (gi GameInfo) FromJson(json_str string) (GameInfo, error) {
 	return gameInfo, nil
 }
 
+// ErrPropertyMissing is returned, along with the SGF default, by the
+// typed accessors when a game-info property is not set.
+var ErrPropertyMissing = errors.New("property not set")
+
+// BoardSize returns the SZ value, which is either one number for a square
+// board or "width:height" for a rectangular one. A missing SZ gives the
+// default 19x19 with ErrPropertyMissing, and a side outside 1..52 gives an
+// error.
+func (gi GameInfo) BoardSize() (width, height int, err error) {
+	value := strings.TrimSpace(gi[Boardsize])
+	if value == "" {
+		return 19, 19, ErrPropertyMissing
+	}
+	parts := strings.SplitN(value, ":", 2)
+	width, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	height = width
+	if err == nil && len(parts) == 2 {
+		height, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	if err != nil {
+		return 0, 0, errors.New(fmt.Sprintf("SZ[%s]: board size is not a number", value))
+	}
+	if width < 1 || width > maxBoardSize || height < 1 || height > maxBoardSize {
+		return 0, 0, errors.New(fmt.Sprintf("SZ[%s]: board size outside 1..%d", value, maxBoardSize))
+	}
+	return width, height, nil
+}
+
+// Komi returns the KM value, or 0 with ErrPropertyMissing if it is not set.
+func (gi GameInfo) Komi() (float64, error) {
+	value := strings.TrimSpace(gi[Komi])
+	if value == "" {
+		return 0, ErrPropertyMissing
+	}
+	komi, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("KM[%s]: komi is not a number", value))
+	}
+	return komi, nil
+}
+
+// Handicap returns the HA value, or 0 with ErrPropertyMissing if it is
+// not set.
+func (gi GameInfo) Handicap() (int, error) {
+	value := strings.TrimSpace(gi[Handicap])
+	if value == "" {
+		return 0, ErrPropertyMissing
+	}
+	handicap, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("HA[%s]: handicap is not a number", value))
+	}
+	return handicap, nil
+}
+
+// KomiNormalized returns komi as white's bonus. Some files record it as a
+// negative number; this assumes reverse komi is never intended and flips
+// the sign, reporting whether it did so.
+func (gi GameInfo) KomiNormalized() (komi float64, flipped bool) {
+	komi, err := strconv.ParseFloat(strings.TrimSpace(gi[Komi]), 64)
+	if err != nil {
+		return 0, false
+	}
+	if komi < 0 {
+		return -komi, true
+	}
+	return komi, false
+}
+
+// EffectiveKomi returns komi adjusted for handicap under the game's rules.
+// Area scoring counts the handicap stones, so white is compensated for
+// them: under Chinese rules by one point per stone, and under AGA rules by
+// one point for each stone after the first. Other rules leave KM as it is.
+func (gi GameInfo) EffectiveKomi() float64 {
+	komi, err := strconv.ParseFloat(strings.TrimSpace(gi[Komi]), 64)
+	if err != nil {
+		komi = 0
+	}
+	handicap, err := strconv.Atoi(strings.TrimSpace(gi[Handicap]))
+	if err != nil || handicap < 2 {
+		return komi
+	}
+	rules := strings.ToLower(strings.TrimSpace(gi[Rules]))
+	switch {
+	case rules == "cn" || strings.Contains(rules, "chinese"):
+		return komi + float64(handicap)
+	case strings.Contains(rules, "aga"):
+		return komi + float64(handicap-1)
+	}
+	return komi
+}
+
+// PlaceNormalizer, if set, maps a tidied PC value onto a canonical venue
+// name so that differently written venues compare equal.
+var PlaceNormalizer func(place string) string
+
+// Place returns the PC value with its whitespace collapsed to single
+// spaces, passed through PlaceNormalizer if one is set.
+func (gi GameInfo) Place() (string, bool) {
+	place := strings.Join(strings.Fields(gi[Place]), " ")
+	if place == "" {
+		return "", false
+	}
+	if PlaceNormalizer != nil {
+		place = PlaceNormalizer(place)
+	}
+	return place, true
+}
+
 func (gi GameInfo) clone() map[string]string {
 	duplicate := make(map[string]string)
 	for k, v := range gi {
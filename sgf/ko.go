@@ -0,0 +1,36 @@
+package sgf
+
+type ko struct {
+	moveNum int   // the move that took the ko
+	stone   Point // the stone that took it
+}
+
+// KoThreatResolved reports whether the move at moveNum legally retakes a
+// ko: it captures the single stone that took the ko, at least one move
+// (the threat and its answer) has been played elsewhere since, and the
+// resulting position has not occurred before in the game.
+func (sgf *Game) KoThreatResolved(moveNum int) bool {
+	kos := map[Point]ko{}
+	history := map[string]bool{}
+	resolved := false
+
+	sgf.replay(func(n int, node *Node, board *Board) bool {
+		m, isMove := node.move(board.Size)
+		position := board.String()
+		if n == moveNum {
+			k, isKo := kos[m.Point]
+			resolved = isMove && isKo && k.moveNum < n-1 &&
+				len(board.captured) == 1 && board.captured[0] == k.stone &&
+				!history[position]
+			return false
+		}
+		history[position] = true
+		if isMove {
+			if point, isKo := board.KoPoint(m); isKo {
+				kos[point] = ko{n, m.Point}
+			}
+		}
+		return true
+	})
+	return resolved
+}
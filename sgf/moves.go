@@ -0,0 +1,192 @@
+package sgf
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// moveNodes returns the main-line nodes carrying a move, so that the
+// node for move n is at index n-1.
+func (sgf *Game) moveNodes() (nodes []*Node) {
+	for _, node := range sgf.mainLine() {
+		if node.Point.Name != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Tenukis returns the main-line move numbers of moves played further than
+// threshold (Manhattan distance) from the move before, whichever player
+// made it. Passes are skipped.
+func (sgf *Game) Tenukis(threshold int) (moveNums []int) {
+	size := sgf.boardSize()
+	var last *Move
+	for i, node := range sgf.moveNodes() {
+		m, ok := node.move(size)
+		if !ok {
+			continue
+		}
+		if last != nil && last.Point.Distance(m.Point) > threshold {
+			moveNums = append(moveNums, i+1)
+		}
+		last = &m
+	}
+	return moveNums
+}
+
+// Passes returns the main-line move numbers of passes, written either as
+// an empty value or, on boards up to 19x19, as "tt".
+func (sgf *Game) Passes() (moveNums []int) {
+	size := sgf.boardSize()
+	for i, node := range sgf.moveNodes() {
+		if node.Point.IsPass(size) {
+			moveNums = append(moveNums, i+1)
+		}
+	}
+	return moveNums
+}
+
+// MoveHash hashes the root setup stones and main-line moves, so that two
+// copies of a game hash the same whatever their comments or game info.
+func (sgf *Game) MoveHash() uint64 {
+	h := fnv.New64a()
+	for _, prop := range sgf.Setup {
+		if isSetupProperty(prop.Name) {
+			h.Write([]byte(prop.String()))
+		}
+	}
+	h.Write([]byte(";"))
+	for _, node := range sgf.moveNodes() {
+		h.Write([]byte(node.Point.String()))
+	}
+	return h.Sum64()
+}
+
+// MoveCountByColor counts the main-line moves made by each player,
+// passes included.
+func (sgf *Game) MoveCountByColor() (black, white int) {
+	return sgf.moveCountByColor(true)
+}
+
+// MoveCountByColorExcludingPasses counts the main-line moves made by each
+// player, leaving out passes.
+func (sgf *Game) MoveCountByColorExcludingPasses() (black, white int) {
+	return sgf.moveCountByColor(false)
+}
+
+func (sgf *Game) moveCountByColor(passes bool) (black, white int) {
+	size := sgf.boardSize()
+	for _, node := range sgf.moveNodes() {
+		if !passes && node.Point.IsPass(size) {
+			continue
+		}
+		switch node.Point.Name {
+		case "B":
+			black += 1
+		case "W":
+			white += 1
+		}
+	}
+	return black, white
+}
+
+// Lengths counts the nodes on the main line, the root included as in
+// NodeCount, and those of them that are moves rather than setup or
+// annotation.
+func (sgf *Game) Lengths() (nodes, moves int) {
+	return len(sgf.mainLine()) + 1, len(sgf.moveNodes())
+}
+
+// MoveSummaryLine describes the game in one line for logging, such as
+// "19x19 B+R 187mv PB=Alice PW=Bob". Missing values are shown as "?".
+func (sgf *Game) MoveSummaryLine() string {
+	orUnknown := func(name string) string {
+		if value := strings.TrimSpace(sgf.GameInfo[name]); value != "" {
+			return value
+		}
+		return "?"
+	}
+	size := sgf.boardSize()
+	return fmt.Sprintf("%dx%d %s %dmv PB=%s PW=%s", size, size, orUnknown(Result),
+		len(sgf.moveNodes()), orUnknown(PlayerBlackName), orUnknown(PlayerWhiteName))
+}
+
+// ScoreDeltas returns the change in the score estimate, the V value,
+// from each main-line move carrying one to the next, so that the deltas
+// add up to the last estimate less the first. A positive change favours
+// black.
+func (sgf *Game) ScoreDeltas() []float64 {
+	_, deltas := sgf.scoreDeltas()
+	return deltas
+}
+
+// scoreDeltas gives ScoreDeltas along with the move number at the end of
+// each change.
+func (sgf *Game) scoreDeltas() (moveNums []int, deltas []float64) {
+	var last *float64
+	for n, node := range sgf.moveNodes() {
+		value, ok := node.GetProperty("V")
+		if !ok {
+			continue
+		}
+		score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+		if last != nil {
+			moveNums = append(moveNums, n+1)
+			deltas = append(deltas, score-*last)
+		}
+		last = &score
+	}
+	return moveNums, deltas
+}
+
+// BiggestSwing returns the main-line move whose score estimate, its V
+// value, differs most from the last estimate before it, along with that
+// change. A positive change favours black. moveNum is 0 when fewer than
+// two moves carry an estimate.
+func (sgf *Game) BiggestSwing() (moveNum int, delta float64) {
+	moveNums, deltas := sgf.scoreDeltas()
+	for n, d := range deltas {
+		if math.Abs(d) > math.Abs(delta) {
+			moveNum, delta = moveNums[n], d
+		}
+	}
+	return moveNum, delta
+}
+
+// AnnotatedMoves returns the main-line move numbers carrying a comment,
+// markup or annotation. Annotations on a node without a move count
+// towards the move before it.
+func (sgf *Game) AnnotatedMoves() (moveNums []int) {
+	moveNum := 0
+	for _, node := range sgf.mainLine() {
+		if node.Point.Name != "" {
+			moveNum += 1
+		}
+		if moveNum == 0 || (len(moveNums) > 0 && moveNums[len(moveNums)-1] == moveNum) {
+			continue
+		}
+		for _, prop := range node.Properties {
+			if isAnnotationProperty(prop.Name) {
+				moveNums = append(moveNums, moveNum)
+				break
+			}
+		}
+	}
+	return moveNums
+}
+
+func isAnnotationProperty(name string) bool {
+	switch name {
+	case "C", "AR", "CR", "LB", "LN", "MA", "SL", "SQ", "TR",
+		"DM", "GB", "GW", "HO", "UC", "V", "BM", "DO", "IT", "TE":
+		return true
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package sgf
 
+import "strings"
+
 type Node struct {
 	Point      Property
 	Properties []Property
@@ -24,16 +26,19 @@ func (node Node) variationString() string {
 }
 
 func (node Node) propertiesString() string {
-	str := ""
-	for _, prop := range node.Properties {
-		str += prop.String()
+	return propertiesString(node.Properties)
+}
+
+func (node Node) pointString() string {
+	if node.Point.Name == "" {
+		return ""
 	}
-	return str
+	return node.Point.String()
 }
 
 func (node Node) String() string {
 	return ";" +
-		node.Point.String() +
+		node.pointString() +
 		node.propertiesString() +
 		node.variationString()
 }
@@ -57,3 +62,142 @@ func (n *Node) NewVariation() *Node {
 	n.Variations = append(n.Variations, node)
 	return node
 }
+
+// Equal reports whether node and other hold the same move, properties,
+// variations and continuation.
+func (node *Node) Equal(other *Node) bool {
+	if node == nil || other == nil {
+		return node == other
+	}
+	if node.Point != other.Point ||
+		len(node.Properties) != len(other.Properties) ||
+		len(node.Variations) != len(other.Variations) {
+		return false
+	}
+	for i, prop := range node.Properties {
+		if prop != other.Properties[i] {
+			return false
+		}
+	}
+	for i, nodevar := range node.Variations {
+		if !nodevar.Equal(other.Variations[i]) {
+			return false
+		}
+	}
+	return node.Next.Equal(other.Next)
+}
+
+// dedupeVariations drops variations that repeat the next node or an
+// earlier variation, at node and every branch point below it.
+func (node *Node) dedupeVariations() {
+	if node == nil {
+		return
+	}
+	var variations []*Node
+	for _, nodevar := range node.Variations {
+		duplicate := node.Next.Equal(nodevar)
+		for _, kept := range variations {
+			duplicate = duplicate || kept.Equal(nodevar)
+		}
+		if !duplicate {
+			variations = append(variations, nodevar)
+		}
+	}
+	node.Variations = variations
+
+	for _, child := range node.children() {
+		child.dedupeVariations()
+	}
+}
+
+func (node *Node) isEmpty() bool {
+	return node.Point.Name == "" && len(node.Properties) == 0
+}
+
+// trimEmpty drops empty nodes from the end of each line starting at node,
+// returning nil if nothing meaningful remains.
+func (node *Node) trimEmpty() *Node {
+	if node == nil {
+		return nil
+	}
+	node.Next = node.Next.trimEmpty()
+
+	var variations []*Node
+	for _, nodevar := range node.Variations {
+		if nodevar = nodevar.trimEmpty(); nodevar != nil {
+			variations = append(variations, nodevar)
+		}
+	}
+	node.Variations = variations
+
+	if node.Next == nil && len(node.Variations) == 0 && node.isEmpty() {
+		return nil
+	}
+	return node
+}
+
+// mainChild returns the node that continues the main line: the next node
+// in sequence or, at a branch point, the first variation.
+func (node *Node) mainChild() *Node {
+	if node.Next != nil {
+		return node.Next
+	}
+	if len(node.Variations) > 0 {
+		return node.Variations[0]
+	}
+	return nil
+}
+
+func (node *Node) children() []*Node {
+	if node.Next == nil {
+		return node.Variations
+	}
+	return append([]*Node{node.Next}, node.Variations...)
+}
+
+func (node *Node) GetProperty(name string) (value string, ok bool) {
+	for _, prop := range node.Properties {
+		if prop.Name == strings.ToUpper(name) {
+			return prop.Value, true
+		}
+	}
+	return "", false
+}
+
+// PropertyValues returns every value of the named property, in order.
+func (node *Node) PropertyValues(name string) (values []string) {
+	name = strings.ToUpper(name)
+	if node.Point.Name == name {
+		values = append(values, node.Point.Value)
+	}
+	for _, prop := range node.Properties {
+		if prop.Name == name {
+			values = append(values, prop.Value)
+		}
+	}
+	return values
+}
+
+// Move returns the colour ("B" or "W") and point of the move made at
+// node, with color empty if there is none. An empty value is a pass; "tt",
+// which older files use for a pass on boards up to 19x19, is returned as
+// a point since the node doesn't know the board size.
+func (node *Node) Move() (color string, point Point, isPass bool) {
+	if node.Point.Name != "B" && node.Point.Name != "W" {
+		return "", Point{}, false
+	}
+	point, err := node.Point.Point()
+	return node.Point.Name, point, err == ErrPass
+}
+
+// subtree returns node and all of its descendants in pre-order.
+func (node *Node) subtree() (nodes []*Node) {
+	if node == nil {
+		return nil
+	}
+	nodes = append(nodes, node)
+	for _, child := range node.children() {
+		nodes = append(nodes, child.subtree()...)
+	}
+	return nodes
+}
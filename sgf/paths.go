@@ -0,0 +1,111 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FindLine looks for a line of play from the start of the game matching
+// moves, returning the path to it: the index of the child taken at each
+// branch point.
+func (sgf *Game) FindLine(moves []Move) ([]int, bool) {
+	size := sgf.boardSize()
+
+	var find func(node *Node, moves []Move, path []int) ([]int, bool)
+	find = func(node *Node, moves []Move, path []int) ([]int, bool) {
+		if len(moves) == 0 {
+			return path, true
+		}
+		if node == nil {
+			return nil, false
+		}
+		if node.Point.Name != "" {
+			if m, ok := node.move(size); !ok || m != moves[0] {
+				return nil, false
+			}
+			moves = moves[1:]
+			if len(moves) == 0 {
+				return path, true
+			}
+		}
+		children := node.children()
+		if len(children) == 1 {
+			return find(children[0], moves, path)
+		}
+		for i, child := range children {
+			if found, ok := find(child, moves, append(path[:len(path):len(path)], i)); ok {
+				return found, true
+			}
+		}
+		return nil, false
+	}
+	return find(sgf.GameTree, moves, []int{})
+}
+
+// lineAtPath follows path from the start of the game, taking the given
+// child at each branch point and the first child once path runs out, and
+// returns the nodes of the line to its end.
+func (sgf *Game) lineAtPath(path []int) (line []*Node, err error) {
+	for node := sgf.GameTree; node != nil; {
+		line = append(line, node)
+		children := node.children()
+		if len(children) < 2 || len(path) == 0 {
+			node = node.mainChild()
+			continue
+		}
+		if path[0] < 0 || path[0] >= len(children) {
+			return nil, errors.New(fmt.Sprintf("no variation %d at node %d", path[0], len(line)))
+		}
+		node, path = children[path[0]], path[1:]
+	}
+	if len(path) > 0 {
+		return nil, errors.New("path continues past the end of the line")
+	}
+	return line, nil
+}
+
+// NodeAtPath returns the last node of the line found by following path,
+// as given by FindLine. The line carries on past the end of path, taking
+// the first child at each branch point, so a path from TableOfContents
+// picks out the line a named node is on rather than the node itself.
+func (sgf *Game) NodeAtPath(path []int) (*Node, error) {
+	line, err := sgf.lineAtPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("game has no nodes")
+	}
+	return line[len(line)-1], nil
+}
+
+// BoardAtPath replays the line found by following path and returns the
+// position at its end.
+func (sgf *Game) BoardAtPath(path []int) (*Board, error) {
+	line, err := sgf.lineAtPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return sgf.replayLine(line, nil)
+}
+
+// SiblingsOf returns the continuations available at the parent of node,
+// node among them, and the index of node in that list, for stepping
+// between variations. The first node of the game is its own only
+// sibling. The index is -1 if node is not in the game.
+func (sgf *Game) SiblingsOf(node *Node) ([]*Node, int) {
+	line := sgf.lineTo(node)
+	if line == nil {
+		return nil, -1
+	}
+	if len(line) == 1 {
+		return []*Node{node}, 0
+	}
+	siblings := line[len(line)-2].children()
+	for i, sibling := range siblings {
+		if sibling == node {
+			return siblings, i
+		}
+	}
+	return nil, -1
+}
@@ -0,0 +1,65 @@
+package sgf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type PlayerInfo struct {
+	name string
+	rank string
+	team string
+}
+
+// Black returns the PB, BR and BT values.
+func (sgf *Game) Black() PlayerInfo {
+	return PlayerInfo{sgf.GameInfo[PlayerBlackName], sgf.GameInfo[PlayerBlackRank], sgf.GameInfo[PlayerBlackTeam]}
+}
+
+// White returns the PW, WR and WT values.
+func (sgf *Game) White() PlayerInfo {
+	return PlayerInfo{sgf.GameInfo[PlayerWhiteName], sgf.GameInfo[PlayerWhiteRank], sgf.GameInfo[PlayerWhiteTeam]}
+}
+
+func (pi PlayerInfo) Name() string {
+	return pi.name
+}
+
+func (pi PlayerInfo) Rank() string {
+	return pi.rank
+}
+
+func (pi PlayerInfo) Team() string {
+	return pi.team
+}
+
+// Grade is the player's rank on the scale used by NormalizeRank.
+func (pi PlayerInfo) Grade() (int, bool) {
+	return NormalizeRank(pi.rank)
+}
+
+var rankPattern = regexp.MustCompile(`^(\d+)\s*(k|kyu|d|dan|p|pro)$`)
+
+// NormalizeRank maps a rank such as "3k", "5 dan" or "7p" onto a single
+// scale for comparing strength: kyu ranks are negative (3k is -3), dan
+// ranks positive (5d is 5), and professional ranks come above 9d (1p is
+// 10). The uncertainty markers "?" and "*" are ignored.
+func NormalizeRank(s string) (grade int, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), "?*")))
+	match := rankPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	switch match[2] {
+	case "k", "kyu":
+		return -n, true
+	case "d", "dan":
+		return n, true
+	}
+	return 9 + n, true
+}
@@ -1,6 +1,10 @@
 package sgf
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
 type Point struct {
 	X rune
@@ -10,3 +14,110 @@ type Point struct {
 func (point Point) String() string {
 	return fmt.Sprintf("[%c%c]", point.X, point.Y)
 }
+
+// PointCodec converts between points and their text in property values.
+type PointCodec interface {
+	Encode(Point) string
+	Decode(string) (Point, error)
+}
+
+// LetterCodec is the standard SGF encoding of a point as two letters.
+type LetterCodec struct{}
+
+func (LetterCodec) Encode(point Point) string {
+	return string([]rune{point.X, point.Y})
+}
+
+func (LetterCodec) Decode(value string) (Point, error) {
+	runes := []rune(value)
+	if len(runes) != 2 || letterIndex(runes[0]) < 0 || letterIndex(runes[1]) < 0 {
+		return Point{}, errors.New(fmt.Sprintf("invalid point: %q", value))
+	}
+	return Point{runes[0], runes[1]}, nil
+}
+
+// ParsePoint reads a point written in SGF's letter coordinates. Points in
+// other encodings are converted to letters as they are parsed, with
+// ParserConfig.PointCodec.
+func ParsePoint(value string) (Point, error) {
+	return LetterCodec{}.Decode(value)
+}
+
+// SGF returns the point as written in a property value.
+func (point Point) SGF() string {
+	return LetterCodec{}.Encode(point)
+}
+
+// Recode rewrites the value of a move or point property from the encoding
+// of one codec to that of another. Other properties, and values that from
+// cannot read, are returned as they are.
+func (p Property) Recode(from, to PointCodec) Property {
+	spec, ok := PropertyInfo(p.Name)
+	if !ok || p.Value == "" {
+		return p
+	}
+	switch spec.ValueType {
+	case "move", "stone", "point":
+	default:
+		return p
+	}
+	point, err := from.Decode(p.Value)
+	if err != nil {
+		return p
+	}
+	return Property{p.Name, to.Encode(point)}
+}
+
+// maxBoardSize is the largest board whose points have SGF letters.
+const maxBoardSize = 52
+
+// Coords returns the 0-based column and row of the point, mapping the SGF
+// letters a-z onto 0-25 and A-Z onto 26-51. A letter outside those gives
+// -1.
+func (point Point) Coords() (col, row int) {
+	return letterIndex(point.X), letterIndex(point.Y)
+}
+
+func pointAt(x, y int) Point {
+	return Point{indexLetter(x), indexLetter(y)}
+}
+
+func letterIndex(r rune) int {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return int(r - 'a')
+	case r >= 'A' && r <= 'Z':
+		return int(r-'A') + 26
+	}
+	return -1
+}
+
+func indexLetter(i int) rune {
+	if i < 26 {
+		return 'a' + rune(i)
+	}
+	return 'A' + rune(i-26)
+}
+
+// Distance returns the Manhattan distance between the points, the number
+// of steps along the lines of the board from one to the other.
+func (p Point) Distance(q Point) int {
+	px, py := p.Coords()
+	qx, qy := q.Coords()
+	return abs(px-qx) + abs(py-qy)
+}
+
+// EuclideanDistance returns the straight-line distance between the
+// points, in units of the board's line spacing.
+func (p Point) EuclideanDistance(q Point) float64 {
+	px, py := p.Coords()
+	qx, qy := q.Coords()
+	return math.Hypot(float64(px-qx), float64(py-qy))
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
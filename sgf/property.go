@@ -1,6 +1,10 @@
 package sgf
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 type Property struct {
 	Name  string
@@ -8,5 +12,45 @@ type Property struct {
 }
 
 func (p Property) String() string {
-	return fmt.Sprintf("%s[%s]", p.Name, p.Value)
+	return fmt.Sprintf("%s[%s]", p.Name, escapeValue(p.Value))
+}
+
+// ErrPass is returned by Property.Point for an empty move value.
+var ErrPass = errors.New("pass")
+
+// Point reads the value as a single point, or returns ErrPass if it is
+// empty.
+func (p Property) Point() (Point, error) {
+	if p.Value == "" {
+		return Point{}, ErrPass
+	}
+	return ParsePoint(p.Value)
+}
+
+// escapeValue escapes the characters that would otherwise end a value or
+// start an escape.
+func escapeValue(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	return strings.Replace(value, "]", "\\]", -1)
+}
+
+// propertiesString writes props in order, giving consecutive values of
+// the same property as one list: AB[dd][ee] rather than AB[dd]AB[ee].
+func propertiesString(props []Property) string {
+	str := ""
+	for i, prop := range props {
+		if i > 0 && props[i-1].Name == prop.Name {
+			str += "[" + escapeValue(prop.Value) + "]"
+		} else {
+			str += prop.String()
+		}
+	}
+	return str
+}
+
+// isListProperty reports whether name may take a list of values, each
+// held as a separate Property.
+func isListProperty(name string) bool {
+	spec, ok := PropertyInfo(strings.ToUpper(name))
+	return ok && spec.List
 }
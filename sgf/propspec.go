@@ -0,0 +1,122 @@
+package sgf
+
+// NodeType is the kind of node a property belongs in, as given by the
+// FF[4] specification.
+type NodeType int
+
+const (
+	AnyNode NodeType = iota // no restriction ("-" in the specification)
+	MoveNode
+	SetupNode
+	RootNode
+	GameInfoNode
+)
+
+// PropertySpec describes an FF[4] property.
+type PropertySpec struct {
+	// ValueType is the value type as the specification writes it, such
+	// as "point", "simpletext" or "point:simpletext". Alternatives are
+	// separated by "|".
+	ValueType string
+	List      bool // takes a list of values
+	EmptyList bool // the list may be empty, as in VW[]
+	NodeType  NodeType
+	// Group names properties that exclude one another within a node,
+	// such as the move annotations BM, DO, IT and TE. Empty if none.
+	Group string
+}
+
+var propertySpecs = map[string]PropertySpec{
+	// move
+	"B":  {ValueType: "move", NodeType: MoveNode, Group: "move"},
+	"W":  {ValueType: "move", NodeType: MoveNode, Group: "move"},
+	"KO": {ValueType: "none", NodeType: MoveNode},
+	"MN": {ValueType: "number", NodeType: MoveNode},
+
+	// setup
+	"AB": {ValueType: "stone", List: true, NodeType: SetupNode, Group: "setup"},
+	"AW": {ValueType: "stone", List: true, NodeType: SetupNode, Group: "setup"},
+	"AE": {ValueType: "point", List: true, NodeType: SetupNode, Group: "setup"},
+	"PL": {ValueType: "color", NodeType: SetupNode},
+
+	// node annotation
+	"C":  {ValueType: "text"},
+	"DM": {ValueType: "double", Group: "position"},
+	"GB": {ValueType: "double", Group: "position"},
+	"GW": {ValueType: "double", Group: "position"},
+	"HO": {ValueType: "double"},
+	"N":  {ValueType: "simpletext"},
+	"UC": {ValueType: "double", Group: "position"},
+	"V":  {ValueType: "real"},
+
+	// move annotation
+	"BM": {ValueType: "double", NodeType: MoveNode, Group: "move annotation"},
+	"DO": {ValueType: "none", NodeType: MoveNode, Group: "move annotation"},
+	"IT": {ValueType: "none", NodeType: MoveNode, Group: "move annotation"},
+	"TE": {ValueType: "double", NodeType: MoveNode, Group: "move annotation"},
+
+	// markup
+	"AR": {ValueType: "point:point", List: true},
+	"CR": {ValueType: "point", List: true, Group: "markup"},
+	"DD": {ValueType: "point", List: true, EmptyList: true},
+	"LB": {ValueType: "point:simpletext", List: true},
+	"LN": {ValueType: "point:point", List: true},
+	"MA": {ValueType: "point", List: true, Group: "markup"},
+	"SL": {ValueType: "point", List: true, Group: "markup"},
+	"SQ": {ValueType: "point", List: true, Group: "markup"},
+	"TR": {ValueType: "point", List: true, Group: "markup"},
+
+	// root
+	"AP": {ValueType: "simpletext:simpletext", NodeType: RootNode},
+	"CA": {ValueType: "simpletext", NodeType: RootNode},
+	"FF": {ValueType: "number", NodeType: RootNode},
+	"GM": {ValueType: "number", NodeType: RootNode},
+	"ST": {ValueType: "number", NodeType: RootNode},
+	"SZ": {ValueType: "number|number:number", NodeType: RootNode},
+
+	// game info
+	"AN": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"BR": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"BT": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"CP": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"DT": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"EV": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"GC": {ValueType: "text", NodeType: GameInfoNode},
+	"GN": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"HA": {ValueType: "number", NodeType: GameInfoNode},
+	"KM": {ValueType: "real", NodeType: GameInfoNode},
+	"ON": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"OT": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"PB": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"PC": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"PW": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"RE": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"RO": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"RU": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"SO": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"TM": {ValueType: "real", NodeType: GameInfoNode},
+	"US": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"WR": {ValueType: "simpletext", NodeType: GameInfoNode},
+	"WT": {ValueType: "simpletext", NodeType: GameInfoNode},
+
+	// timing
+	"BL": {ValueType: "real", NodeType: MoveNode},
+	"OB": {ValueType: "number", NodeType: MoveNode},
+	"OW": {ValueType: "number", NodeType: MoveNode},
+	"WL": {ValueType: "real", NodeType: MoveNode},
+
+	// miscellaneous
+	"FG": {ValueType: "none|number:simpletext"},
+	"PM": {ValueType: "number"},
+	"VW": {ValueType: "point", List: true, EmptyList: true},
+
+	// Go
+	"TB": {ValueType: "point", List: true, EmptyList: true},
+	"TW": {ValueType: "point", List: true, EmptyList: true},
+}
+
+// PropertyInfo looks up the FF[4] specification of the named property.
+func PropertyInfo(name string) (PropertySpec, bool) {
+	spec, ok := propertySpecs[name]
+	return spec, ok
+}
@@ -0,0 +1,214 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (sgf *Game) mainLine() (nodes []*Node) {
+	for node := sgf.firstNode(); node != nil; node = node.mainChild() {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// lineTo returns the nodes leading from the first node down to target,
+// or nil if target is not in the game.
+func (sgf *Game) lineTo(target *Node) []*Node {
+	var find func(node *Node, line []*Node) []*Node
+	find = func(node *Node, line []*Node) []*Node {
+		line = append(line, node)
+		if node == target {
+			return line
+		}
+		for _, child := range node.children() {
+			if found := find(child, line); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	if sgf.GameTree == nil {
+		return nil
+	}
+	return find(sgf.GameTree, nil)
+}
+
+// boardSize returns the SZ value, or 19 when it is missing or not a
+// size that points can be written on.
+func (sgf *Game) boardSize() int {
+	size, err := strconv.Atoi(strings.TrimSpace(sgf.GameInfo[Boardsize]))
+	if err != nil || size < 1 || size > maxBoardSize {
+		return 19
+	}
+	return size
+}
+
+// InferBoardSize returns the smallest board that holds every point used
+// by moves, setup stones and markup, or false if the game uses none.
+// B[tt] and W[tt] are taken as passes.
+func (sgf *Game) InferBoardSize() (size int, ok bool) {
+	use := func(props []Property) {
+		for _, prop := range props {
+			var parts []string
+			switch {
+			case prop.Name == "B" || prop.Name == "W":
+				if prop.Value != "tt" {
+					parts = []string{prop.Value}
+				}
+			case prop.Name == "LB":
+				parts = strings.Split(prop.Value, ":")[:1]
+			case isSetupProperty(prop.Name) && prop.Name != "PL", isMarkupProperty(prop.Name):
+				parts = strings.Split(prop.Value, ":")
+			}
+			for _, part := range parts {
+				point, valid := Property{Value: part}.point()
+				if !valid {
+					continue
+				}
+				x, y := point.Coords()
+				if x+1 > size {
+					size = x + 1
+				}
+				if y+1 > size {
+					size = y + 1
+				}
+			}
+		}
+	}
+	use(sgf.Setup)
+	for _, node := range sgf.GameTree.subtree() {
+		use(append([]Property{node.Point}, node.Properties...))
+	}
+	return size, size > 0
+}
+
+func (prop Property) point() (Point, bool) {
+	point, err := prop.Point()
+	return point, err == nil
+}
+
+// pointList expands a point value, which may be a compressed
+// rectangle such as "aa:cc".
+func pointList(value string) (points []Point) {
+	corners := strings.Split(value, ":")
+	from, ok := Property{Value: corners[0]}.point()
+	if !ok {
+		return nil
+	}
+	if len(corners) == 1 {
+		return []Point{from}
+	}
+	to, ok := Property{Value: corners[1]}.point()
+	if !ok {
+		return nil
+	}
+	x0, y0 := from.Coords()
+	x1, y1 := to.Coords()
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			points = append(points, pointAt(x, y))
+		}
+	}
+	return points
+}
+
+// IsPass reports whether a move value is a pass on a board of the given
+// size: an empty value, or "tt" in the FF[3] style on boards up to 19x19.
+func (prop Property) IsPass(size int) bool {
+	return prop.Value == "" || (size <= 19 && prop.Value == "tt")
+}
+
+// move returns the stone played at node; ok is false for passes and
+// nodes without a move.
+func (node *Node) move(size int) (m Move, ok bool) {
+	switch node.Point.Name {
+	case "B":
+		m.Color = Black
+	case "W":
+		m.Color = White
+	default:
+		return m, false
+	}
+	if node.Point.IsPass(size) {
+		return m, false
+	}
+	m.Point, ok = node.Point.point()
+	return m, ok
+}
+
+func (b *Board) setup(props []Property) {
+	for _, prop := range props {
+		var color Color
+		switch prop.Name {
+		case "AB":
+			color = Black
+		case "AW":
+			color = White
+		case "AE":
+			color = Empty
+		default:
+			continue
+		}
+		for _, p := range pointList(prop.Value) {
+			if x, y := p.Coords(); b.onBoard(x, y) {
+				b.set(p, color)
+			}
+		}
+	}
+}
+
+// suicideAllowed reports whether the rules named by an RU value permit
+// suicide, as New Zealand and Tromp-Taylor rules do.
+func suicideAllowed(rules string) bool {
+	rules = strings.ToLower(rules)
+	for _, name := range []string{"nz", "new zealand", "tromp"} {
+		if strings.Contains(rules, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// replay plays the main line onto a fresh board, applying setup
+// properties on the way, and calls visit after each move with its move
+// number. Passes are numbered but leave the board untouched. Replay stops
+// early when visit returns false.
+func (sgf *Game) replay(visit func(n int, node *Node, board *Board) bool) (*Board, error) {
+	return sgf.replayLine(sgf.mainLine(), visit)
+}
+
+// ReplayStrict replays the main line and returns the final position, or
+// the first illegal move, such as a move onto a stone of the same colour.
+func (sgf *Game) ReplayStrict() (*Board, error) {
+	return sgf.replay(nil)
+}
+
+// replayLine is replay for any line of play starting at the first node.
+func (sgf *Game) replayLine(line []*Node, visit func(n int, node *Node, board *Board) bool) (*Board, error) {
+	board := NewBoard(sgf.boardSize())
+	board.AllowSuicide = suicideAllowed(sgf.GameInfo[Rules])
+	board.setup(sgf.Setup)
+
+	n := 0
+	for _, node := range line {
+		board.setup(node.Properties)
+		if node.Point.Name == "" {
+			continue
+		}
+		n += 1
+		if m, ok := node.move(board.Size); ok {
+			if _, err := board.Play(m); err != nil {
+				return board, errors.New(fmt.Sprintf("move %d: %s", n, err))
+			}
+		} else {
+			board.captured = nil
+		}
+		if visit != nil && !visit(n, node, board) {
+			break
+		}
+	}
+	return board, nil
+}
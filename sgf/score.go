@@ -0,0 +1,75 @@
+package sgf
+
+// AreaScoreOptions adjusts how AreaScore counts a game.
+type AreaScoreOptions struct {
+	// CountPassStones follows the AGA rule that a player hands the
+	// opponent a stone for each pass, adding a point to the opponent's
+	// score per pass.
+	CountPassStones bool
+}
+
+// AreaScore counts the final position of the main line by area: each
+// player's stones plus the empty regions bordered only by their stones.
+// Stones standing on points marked TB or TW, on the last node marking
+// territory, are taken as dead and removed first. It returns black's
+// margin after komi, positive when black wins. The error is for a main
+// line that can't be replayed or a KM that isn't a number.
+func (sgf *Game) AreaScore(opts AreaScoreOptions) (float64, error) {
+	komi, err := sgf.GameInfo.Komi()
+	if err != nil && err != ErrPropertyMissing {
+		return 0, err
+	}
+	passes := map[string]int{}
+	board, err := sgf.replay(func(n int, node *Node, board *Board) bool {
+		if node.Point.IsPass(board.Size) {
+			passes[node.Point.Name] += 1
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, prop := range sgf.territoryMarks() {
+		owner := Black
+		if prop.Name == "TW" {
+			owner = White
+		}
+		for _, p := range pointList(prop.Value) {
+			if board.At(p) == owner.Opponent() {
+				board.set(p, Empty)
+			}
+		}
+	}
+
+	score := map[Color]int{}
+	seen := map[Point]bool{}
+	for y := 0; y < board.Size; y++ {
+		for x := 0; x < board.Size; x++ {
+			p := pointAt(x, y)
+			if c := board.At(p); c != Empty {
+				score[c] += 1
+				continue
+			}
+			if seen[p] {
+				continue
+			}
+			region, borders := board.region(p)
+			for _, q := range region {
+				seen[q] = true
+			}
+			if borders[Black] != borders[White] {
+				if borders[Black] {
+					score[Black] += len(region)
+				} else {
+					score[White] += len(region)
+				}
+			}
+		}
+	}
+	if opts.CountPassStones {
+		score[Black] += passes["W"]
+		score[White] += passes["B"]
+	}
+	return float64(score[Black]-score[White]) - komi, nil
+}
@@ -0,0 +1,100 @@
+package sgf
+
+import "io"
+
+// SerializeOptions controls how Serialize writes a game.
+type SerializeOptions struct {
+	// StampFormat adds FF[4] and GM[1] to the root when they are missing,
+	// so strict readers know the file is FF[4] Go.
+	StampFormat bool
+
+	// PassAsTT writes passes as tt, for older readers, on boards up to
+	// 19x19. Otherwise passes are written with an empty value as FF[4]
+	// asks.
+	PassAsTT bool
+
+	// PointCodec, if set, writes moves and points in its encoding rather
+	// than SGF's letters. Passes are written as usual.
+	PointCodec PointCodec
+}
+
+// Serialize returns the game in SGF form. String is Serialize with the
+// zero options.
+func (sgf Game) Serialize(opts SerializeOptions) string {
+	info := sgf.GameInfo
+	if opts.StampFormat {
+		info = GameInfo(info.clone())
+		if _, ok := info[FileFormat]; !ok {
+			info[FileFormat] = "4"
+		}
+		if _, ok := info[GameType]; !ok {
+			info[GameType] = "1"
+		}
+	}
+	size := sgf.boardSize()
+	pass := ""
+	if opts.PassAsTT && size <= 19 {
+		pass = "tt"
+	}
+	sgf.GameTree = withPasses(sgf.GameTree, size, pass)
+	if opts.PointCodec != nil {
+		sgf.Setup = recoded(sgf.Setup, opts.PointCodec)
+		sgf.GameTree = withCodec(sgf.GameTree, size, opts.PointCodec)
+	}
+	return "(" + info.String() + sgf.setupString() + sgf.GameTreeString() + ")"
+}
+
+// withPasses copies the tree from node with every pass written as pass.
+func withPasses(node *Node, size int, pass string) *Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	if copied.Point.Name != "" && copied.Point.IsPass(size) {
+		copied.Point.Value = pass
+	}
+	copied.Next = withPasses(node.Next, size, pass)
+	copied.Variations = nil
+	for _, nodevar := range node.Variations {
+		copied.Variations = append(copied.Variations, withPasses(nodevar, size, pass))
+	}
+	return &copied
+}
+
+// withCodec copies the tree from node with every move and point, other
+// than passes, written in codec's encoding.
+func withCodec(node *Node, size int, codec PointCodec) *Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	if copied.Point.Name != "" && !copied.Point.IsPass(size) {
+		copied.Point = copied.Point.Recode(LetterCodec{}, codec)
+	}
+	copied.Properties = recoded(node.Properties, codec)
+	copied.Next = withCodec(node.Next, size, codec)
+	copied.Variations = nil
+	for _, nodevar := range node.Variations {
+		copied.Variations = append(copied.Variations, withCodec(nodevar, size, codec))
+	}
+	return &copied
+}
+
+// recoded returns a copy of props with moves and points in codec's
+// encoding.
+func recoded(props []Property, codec PointCodec) []Property {
+	if props == nil {
+		return nil
+	}
+	copied := make([]Property, len(props))
+	for i, prop := range props {
+		copied[i] = prop.Recode(LetterCodec{}, codec)
+	}
+	return copied
+}
+
+// WriteTo writes the game in SGF form to w.
+func (sgf Game) WriteTo(w io.Writer) (n int64, err error) {
+	written, err := io.WriteString(w, sgf.String())
+	return int64(written), err
+}
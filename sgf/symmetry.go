@@ -0,0 +1,43 @@
+package sgf
+
+// symmetries are the eight rotations and reflections of a square board,
+// mapping (x, y) given the largest coordinate m.
+var symmetries = []func(x, y, m int) (int, int){
+	func(x, y, m int) (int, int) { return x, y },
+	func(x, y, m int) (int, int) { return m - x, y },
+	func(x, y, m int) (int, int) { return x, m - y },
+	func(x, y, m int) (int, int) { return m - x, m - y },
+	func(x, y, m int) (int, int) { return y, x },
+	func(x, y, m int) (int, int) { return m - y, x },
+	func(x, y, m int) (int, int) { return y, m - x },
+	func(x, y, m int) (int, int) { return m - y, m - x },
+}
+
+// OpeningFingerprint describes the first moves of the main line in a form
+// shared by every rotation and reflection of the same opening.
+func (sgf *Game) OpeningFingerprint(moves int) string {
+	size := sgf.boardSize()
+	nodes := sgf.moveNodes()
+	if moves < len(nodes) {
+		nodes = nodes[:moves]
+	}
+
+	fingerprint := ""
+	for _, symmetry := range symmetries {
+		str := ""
+		for _, node := range nodes {
+			value := ""
+			if m, ok := node.move(size); ok {
+				x, y := m.Point.Coords()
+				value = pointAt(symmetry(x, y, size-1)).String()
+			} else {
+				value = "[]"
+			}
+			str += node.Point.Name + value
+		}
+		if fingerprint == "" || str < fingerprint {
+			fingerprint = str
+		}
+	}
+	return fingerprint
+}
@@ -0,0 +1,50 @@
+package sgf
+
+import "fmt"
+
+// VerifyTerritory replays the main line and checks the TB and TW points
+// marked on the last node that has any against the final position. Each
+// marked point should be empty, in an empty region bordered only by stones
+// of the colour claiming it. The problems found are returned as messages;
+// the error is for a main line that can't be replayed.
+func (sgf *Game) VerifyTerritory() (problems []string, err error) {
+	board, err := sgf.replay(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[Color]string{Black: "black", White: "white"}
+	for _, prop := range sgf.territoryMarks() {
+		owner := Black
+		if prop.Name == "TW" {
+			owner = White
+		}
+		for _, p := range pointList(prop.Value) {
+			if c := board.At(p); c != Empty {
+				problems = append(problems, fmt.Sprintf("%s[%s]: point holds a %s stone", prop.Name, p.SGF(), names[c]))
+				continue
+			}
+			if _, borders := board.region(p); borders[owner.Opponent()] {
+				problems = append(problems, fmt.Sprintf("%s[%s]: region borders %s stones", prop.Name, p.SGF(), names[owner.Opponent()]))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// territoryMarks returns the TB and TW properties of the last main-line
+// node that has any.
+func (sgf *Game) territoryMarks() (marked []Property) {
+	for _, node := range sgf.mainLine() {
+		var props []Property
+		for _, prop := range node.Properties {
+			if prop.Name == "TB" || prop.Name == "TW" {
+				props = append(props, prop)
+			}
+		}
+		if len(props) > 0 {
+			marked = props
+		}
+	}
+	return marked
+}
@@ -0,0 +1,174 @@
+package sgf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the game for content that parses but is probably
+// wrong, returning a warning for each problem found.
+func (sgf *Game) Validate() (warnings []error) {
+	for _, check := range validators {
+		warnings = append(warnings, check(sgf)...)
+	}
+	return warnings
+}
+
+var validators = []func(*Game) []error{
+	validateKomi,
+	validateBoardSize,
+	validateInferredSize,
+	validateMarkup,
+	validateFirstMove,
+	validateSetupPoints,
+}
+
+func warning(format string, args ...interface{}) error {
+	return errors.New(fmt.Sprintf(format, args...))
+}
+
+// validateKomi flags komi that is not a whole or half point, which usually
+// means the value was garbled.
+func validateKomi(sgf *Game) []error {
+	value, ok := sgf.GameInfo[Komi]
+	if !ok {
+		return nil
+	}
+	komi, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return []error{warning("KM[%s]: komi is not a number", value)}
+	}
+	if frac := math.Abs(komi - math.Trunc(komi)); frac != 0 && frac != 0.5 {
+		return []error{warning("KM[%s]: komi is not a whole or half point", value)}
+	}
+	return nil
+}
+
+// validateBoardSize flags an SZ outside 1 to 52 on either side, the
+// sizes that points can be written on.
+func validateBoardSize(sgf *Game) []error {
+	value, ok := sgf.GameInfo[Boardsize]
+	if !ok {
+		return nil
+	}
+	for _, side := range strings.Split(value, ":") {
+		size, err := strconv.Atoi(strings.TrimSpace(side))
+		if err != nil {
+			return []error{warning("SZ[%s]: board size is not a number", value)}
+		}
+		if size < 1 || size > maxBoardSize {
+			return []error{warning("SZ[%s]: board size outside 1..%d", value, maxBoardSize)}
+		}
+	}
+	return nil
+}
+
+// validateMarkup flags markup that points off the board. Nodes are
+// numbered in tree order, starting at 1 for the first node after the root;
+// the root is reported as node 0.
+func validateMarkup(sgf *Game) (warnings []error) {
+	size := sgf.boardSize()
+	check := func(n int, props []Property) {
+		for _, prop := range props {
+			if !isMarkupProperty(prop.Name) || prop.Value == "" {
+				continue
+			}
+			parts := strings.Split(prop.Value, ":")
+			if prop.Name == "LB" {
+				parts = parts[:1]
+			}
+			for _, part := range parts {
+				point, ok := Property{Value: part}.point()
+				x, y := point.Coords()
+				if !ok || x >= size || y >= size {
+					warnings = append(warnings, warning("node %d: %s: point off the board", n, prop))
+					break
+				}
+			}
+		}
+	}
+	check(0, sgf.Setup)
+	for n, node := range sgf.GameTree.subtree() {
+		check(n+1, node.Properties)
+	}
+	return warnings
+}
+
+// validateFirstMove flags white moving first in a game with no handicap
+// and no setup stones, which suggests the colours were swapped.
+func validateFirstMove(sgf *Game) []error {
+	if handicap, err := strconv.Atoi(strings.TrimSpace(sgf.GameInfo[Handicap])); err == nil && handicap > 1 {
+		return nil
+	}
+	if hasSetupStones(sgf.Setup) {
+		return nil
+	}
+	for n, node := range sgf.mainLine() {
+		if hasSetupStones(node.Properties) {
+			return nil
+		}
+		switch node.Point.Name {
+		case "W":
+			return []error{warning("node %d: %s: white moves first in an even game", n+1, node.Point)}
+		case "B":
+			return nil
+		}
+	}
+	return nil
+}
+
+// validateSetupPoints flags a point listed twice within one AB, AW or AE
+// property. The root is reported as node 0.
+func validateSetupPoints(sgf *Game) (warnings []error) {
+	check := func(n int, props []Property) {
+		var seen map[Point]bool
+		for i, prop := range props {
+			if !isSetupProperty(prop.Name) || prop.Name == "PL" {
+				continue
+			}
+			if i == 0 || props[i-1].Name != prop.Name {
+				seen = map[Point]bool{}
+			}
+			for _, point := range pointList(prop.Value) {
+				if seen[point] {
+					warnings = append(warnings, warning("node %d: %s: duplicate point %s", n, prop.Name, point))
+				}
+				seen[point] = true
+			}
+		}
+	}
+	check(0, sgf.Setup)
+	for n, node := range sgf.GameTree.subtree() {
+		check(n+1, node.Properties)
+	}
+	return warnings
+}
+
+func hasSetupStones(props []Property) bool {
+	for _, prop := range props {
+		if prop.Name == "AB" || prop.Name == "AW" {
+			return true
+		}
+	}
+	return false
+}
+
+func isMarkupProperty(name string) bool {
+	switch name {
+	case "AR", "CR", "DD", "LB", "LN", "MA", "SL", "SQ", "TR", "TB", "TW", "VW":
+		return true
+	}
+	return false
+}
+
+// validateInferredSize flags a board too small for the points used.
+func validateInferredSize(sgf *Game) []error {
+	inferred, ok := sgf.InferBoardSize()
+	if size := sgf.boardSize(); ok && inferred > size {
+		return []error{warning("SZ[%d]: points used need a board of at least %d", size, inferred)}
+	}
+	return nil
+}
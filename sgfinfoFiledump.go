@@ -3,19 +3,13 @@ package main
 import (
   "fmt"
 
-  "github.com/dhodges/sgfinfo/util"
   "github.com/dhodges/sgfinfo/parse"
 )
 
 func dumpFileInfo(filename string) {
-  str, err := util.File2string(filename)
+  games, err := parse.ParseFile(filename)
   if err != nil {
-    errorAndExit(fmt.Sprintf("\nproblem reading file: %s", filename))
-  }
-
-  games, err := parse.ParseString(str)
-  if err != nil {
-    errorAndExit(fmt.Sprintf("\nproblem parsing file: %s", filename))
+    errorAndExit(fmt.Sprintf("\nproblem reading file: %s", err))
   }
 
   // for now, assume one game per file
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func playMoves(board *sgf.Board, color sgf.Color, points ...string) {
+	for _, p := range points {
+		board.Play(sgf.Move{Color: color, Point: sgf.Point{X: rune(p[0]), Y: rune(p[1])}})
+	}
+}
+
+func TestKoPoint(t *testing.T) {
+	board := sgf.NewBoard(9)
+	playMoves(board, sgf.Black, "ba", "ab", "bc")
+	playMoves(board, sgf.White, "ca", "bb", "db", "cc")
+
+	move := sgf.Move{Color: sgf.Black, Point: sgf.Point{X: 'c', Y: 'b'}}
+	captured, err := board.Play(move)
+	assert.Equal(t, err, nil, "problem playing move")
+	assert.Equal(t, captured, []sgf.Point{{X: 'b', Y: 'b'}}, "wrong stones captured")
+
+	point, ok := board.KoPoint(move)
+	assert.Equal(t, ok, true, "ko not found")
+	assert.Equal(t, point, sgf.Point{X: 'b', Y: 'b'}, "wrong ko point")
+}
+
+func TestNoKoPoint(t *testing.T) {
+	board := sgf.NewBoard(9)
+	playMoves(board, sgf.Black, "dd")
+
+	move := sgf.Move{Color: sgf.White, Point: sgf.Point{X: 'e', Y: 'e'}}
+	board.Play(move)
+
+	_, ok := board.KoPoint(move)
+	assert.Equal(t, ok, false, "unexpected ko")
+}
+
+func TestToSetup(t *testing.T) {
+	board := sgf.NewBoard(9)
+	playMoves(board, sgf.Black, "cc", "gc", "ee")
+	playMoves(board, sgf.White, "cg", "gg")
+
+	ab, aw := board.ToSetup()
+	assert.Equal(t, len(ab), 3, "wrong number of black stones")
+	assert.Equal(t, len(aw), 2, "wrong number of white stones")
+	assert.Equal(t, ab[0], sgf.Point{X: 'c', Y: 'c'}, "wrong black stone")
+	assert.Equal(t, aw[1], sgf.Point{X: 'g', Y: 'g'}, "wrong white stone")
+}
+
+func TestOccupancy(t *testing.T) {
+	board := sgf.NewBoard(19)
+	assert.Equal(t, board.Occupancy(), 0.0, "empty board is unoccupied")
+
+	for n := 0; n < 100; n++ {
+		x, y := n%19, n/19
+		playMoves(board, sgf.Black, string([]rune{'a' + rune(x), 'a' + rune(y)}))
+	}
+	assert.Equal(t, board.Occupancy(), 100.0/361.0, "wrong occupancy")
+}
+
+func TestSuicideUnderRules(t *testing.T) {
+	moves := ";B[ba];W[ca];B[ii];W[bb];B[hh];W[ab];B[aa])"
+
+	games, err := parse.ParseString("(;SZ[9]RU[NZ]" + moves)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	board, err := games[0].BoardAtPath(nil)
+	assert.Equal(t, err, nil, "suicide rejected under NZ rules")
+	assert.Equal(t, board.At(sgf.Point{X: 'a', Y: 'a'}), sgf.Empty, "suicided stone left on board")
+	assert.Equal(t, board.At(sgf.Point{X: 'b', Y: 'a'}), sgf.Empty, "suicided stone left on board")
+
+	games, err = parse.ParseString("(;SZ[9]RU[Japanese]" + moves)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	_, err = games[0].BoardAtPath(nil)
+	assert.NotEqual(t, err, nil, "suicide accepted under Japanese rules")
+}
+
+func TestDame(t *testing.T) {
+	board := sgf.NewBoard(5)
+	playMoves(board, sgf.Black, "ba", "bb", "bc", "bd", "be", "cb", "cc", "cd")
+	playMoves(board, sgf.White, "da", "db", "dc", "dd", "de")
+
+	assert.Equal(t, board.Dame(), []sgf.Point{{X: 'c', Y: 'a'}, {X: 'c', Y: 'e'}}, "wrong dame")
+}
+
+func TestBoundingBox(t *testing.T) {
+	board := sgf.NewBoard(19)
+	_, _, _, _, empty := board.BoundingBox()
+	assert.Equal(t, empty, true, "board has no stones")
+
+	playMoves(board, sgf.Black, "pd", "qf")
+	playMoves(board, sgf.White, "qc", "nc")
+	x0, y0, x1, y1, empty := board.BoundingBox()
+	assert.Equal(t, empty, false, "board has stones")
+	assert.Equal(t, []int{x0, y0, x1, y1}, []int{13, 2, 16, 5}, "wrong bounding box")
+}
+
+func TestReplayStrict(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[9];B[dd];W[ee];B[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	_, err = games[0].ReplayStrict()
+	assert.NotEqual(t, err, nil, "expected an error")
+	assert.Equal(t, err.Error(), "move 3: move on own stone at [dd]", "wrong error")
+
+	games, err = parse.ParseString("(;SZ[9];B[dd];W[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	_, err = games[0].ReplayStrict()
+	assert.NotEqual(t, err, nil, "expected an error")
+	assert.Equal(t, err.Error(), "move 2: point already occupied at [dd]", "wrong error")
+
+	games, err = parse.ParseString(koSetup + ";B[cb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	_, err = games[0].ReplayStrict()
+	assert.Equal(t, err, nil, "capture rejected")
+}
+
+func TestUnicodeString(t *testing.T) {
+	board := sgf.NewBoard(9)
+	playMoves(board, sgf.Black, "cc")
+	playMoves(board, sgf.White, "gg")
+
+	expected := "" +
+		"┌─┬─┬─┬─┬─┬─┬─┬─┐\n" +
+		"├─┼─┼─┼─┼─┼─┼─┼─┤\n" +
+		"├─┼─●─┼─┼─┼─╋─┼─┤\n" +
+		"├─┼─┼─┼─┼─┼─┼─┼─┤\n" +
+		"├─┼─┼─┼─╋─┼─┼─┼─┤\n" +
+		"├─┼─┼─┼─┼─┼─┼─┼─┤\n" +
+		"├─┼─╋─┼─┼─┼─○─┼─┤\n" +
+		"├─┼─┼─┼─┼─┼─┼─┼─┤\n" +
+		"└─┴─┴─┴─┴─┴─┴─┴─┘\n"
+	assert.Equal(t, board.UnicodeString(), expected, "wrong diagram")
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureAt24 surrounds a black stone at dd, with white capturing it on
+// move 24 after a run of unrelated moves.
+func captureAt24() string {
+	black := []string{"dd"}
+	white := []string{"cd", "ed", "dc"}
+	for i := 0; i < 11; i++ {
+		black = append(black, fmt.Sprintf("%cq", 'a'+i))
+	}
+	for i := 0; i < 8; i++ {
+		white = append(white, fmt.Sprintf("%ci", 'a'+i))
+	}
+	white = append(white, "de")
+
+	gameStr := "(;SZ[19]"
+	for i := range black {
+		gameStr += ";B[" + black[i] + "];W[" + white[i] + "]"
+	}
+	return gameStr + ")"
+}
+
+func TestFirstCapture(t *testing.T) {
+	games, err := parse.ParseString(captureAt24())
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	moveNum, captured, ok := games[0].FirstCapture()
+	assert.Equal(t, ok, true, "capture not found")
+	assert.Equal(t, moveNum, 24, "wrong move number")
+	assert.Equal(t, captured, []sgf.Point{{X: 'd', Y: 'd'}}, "wrong stones captured")
+}
+
+func TestNoCapture(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd];W[dp];B[pp];W[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	_, _, ok := games[0].FirstCapture()
+	assert.Equal(t, ok, false, "unexpected capture")
+}
+
+// koRetaken has white play bb, black capture it at move 9, and white
+// retake it at move 12.
+var koRetaken = "(;SZ[9];B[ba];W[ca];B[ab];W[bb];B[bc];W[db];B[ii];W[cc];B[cb];W[hh];B[gg];W[bb])"
+
+func TestOccupiedPoints(t *testing.T) {
+	games, err := parse.ParseString(koRetaken)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	counts := games[0].OccupiedPoints()
+	assert.Equal(t, counts[sgf.Point{X: 'b', Y: 'b'}], 2, "retaken point not counted twice")
+	assert.Equal(t, counts[sgf.Point{X: 'c', Y: 'b'}], 1, "wrong count")
+	assert.Equal(t, len(counts), 11, "wrong number of points")
+}
+
+func TestPointHistory(t *testing.T) {
+	games, err := parse.ParseString(koRetaken)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	events := games[0].PointHistory(sgf.Point{X: 'b', Y: 'b'})
+	assert.Equal(t, events, []sgf.PointEvent{
+		{MoveNumber: 4, Kind: sgf.Placed, Color: sgf.White},
+		{MoveNumber: 9, Kind: sgf.Captured, Color: sgf.White},
+		{MoveNumber: 12, Kind: sgf.Placed, Color: sgf.White},
+	}, "wrong history")
+
+	games, err = parse.ParseString("(;SZ[9];B[ee];W[cc];AE[ee];B[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	events = games[0].PointHistory(sgf.Point{X: 'e', Y: 'e'})
+	assert.Equal(t, events, []sgf.PointEvent{
+		{MoveNumber: 1, Kind: sgf.Placed, Color: sgf.Black},
+		{MoveNumber: 2, Kind: sgf.Cleared, Color: sgf.Empty},
+	}, "wrong history")
+}
+
+func TestNumberedBoard(t *testing.T) {
+	games, err := parse.ParseString(koRetaken)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	board, numbers := games[0].NumberedBoard(4)
+	assert.Equal(t, numbers, map[sgf.Point]int{
+		{X: 'b', Y: 'a'}: 1,
+		{X: 'c', Y: 'a'}: 2,
+		{X: 'a', Y: 'b'}: 3,
+		{X: 'b', Y: 'b'}: 4,
+	}, "wrong move numbers")
+	assert.Equal(t, board.At(sgf.Point{X: 'b', Y: 'c'}), sgf.Empty, "replayed too far")
+
+	_, numbers = games[0].NumberedBoard(9)
+	_, ok := numbers[sgf.Point{X: 'b', Y: 'b'}]
+	assert.Equal(t, ok, false, "captured stone still numbered")
+	assert.Equal(t, numbers[sgf.Point{X: 'c', Y: 'b'}], 9, "capturing move not numbered")
+
+	_, numbers = games[0].NumberedBoard(12)
+	assert.Equal(t, numbers[sgf.Point{X: 'b', Y: 'b'}], 12, "retaken point not renumbered")
+	assert.Equal(t, len(numbers), 10, "wrong number of stones")
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	games := parse.Parse("(;EV[Meijin]PB[Alice];B[pd])(;PB[Bob];B[dd])")
+
+	sgf.Collection(games).ApplyDefaults(sgf.GameInfo{"EV": "Honinbo"})
+	assert.Equal(t, games[0].GameInfo["EV"], "Meijin", "existing event replaced")
+	assert.Equal(t, games[1].GameInfo["EV"], "Honinbo", "default event not applied")
+}
+
+func TestDiffGames(t *testing.T) {
+	a := parse.Parse("(;PB[Alice];B[pd];W[dp])(;B[dd];W[pp])(;AB[dd][pp];W[dp])")
+	b := parse.Parse("(;AB[dd][pp];W[dp]C[handicap game])(;B[qd];W[dc])(;PB[Al];B[pd];W[dp])")
+
+	onlyInA, onlyInB := sgf.Collection(a).DiffGames(b)
+	assert.Equal(t, onlyInA, []int{1}, "wrong games only in the first collection")
+	assert.Equal(t, onlyInB, []int{1}, "wrong games only in the second collection")
+}
+
+func TestWriteCollection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgfinfo")
+	assert.Equal(t, err, nil, "problem creating temp dir")
+	defer os.RemoveAll(dir)
+
+	games := parse.Parse("(;DT[2014-07-06]PB[Alice]PW[Bob];B[pd];W[dp])(;SZ[9];B[ee])")
+	outdir := filepath.Join(dir, "games")
+	err = sgf.WriteCollection(outdir, games, nil)
+	assert.Equal(t, err, nil, "problem writing collection")
+
+	for i, name := range []string{"Alice-vs-Bob-2014-07-06.sgf", "2.sgf"} {
+		written, err := parse.ParseFile(filepath.Join(outdir, name))
+		assert.Equal(t, err, nil, "problem reading written game")
+		assert.Equal(t, written[0].GameInfo, games[i].GameInfo, "game info differs")
+		assert.Equal(t, written[0].GameTree.Equal(games[i].GameTree), true, "game tree differs")
+	}
+}
+
+func TestWriteCollectionNameClashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgfinfo")
+	assert.Equal(t, err, nil, "problem creating temp dir")
+	defer os.RemoveAll(dir)
+
+	games := parse.Parse("(;DT[2014-07-06]PB[Alice]PW[Bob];B[pd])(;DT[2014-07-06]PB[Alice]PW[Bob];B[dd])" +
+		"(;PB[A/C: 1]PW[B\\\\D?];B[pp])(;PB[..]PW[];B[dp])")
+	err = sgf.WriteCollection(dir, games, nil)
+	assert.Equal(t, err, nil, "problem writing collection")
+
+	for i, name := range []string{"Alice-vs-Bob-2014-07-06.sgf", "Alice-vs-Bob-2014-07-06-2.sgf", "A_C_ 1-vs-B_D_.sgf", "4.sgf"} {
+		written, err := parse.ParseFile(filepath.Join(dir, name))
+		assert.Equal(t, err, nil, "problem reading written game")
+		assert.Equal(t, written[0].GameTree.Equal(games[i].GameTree), true, "game tree differs")
+	}
+
+	err = sgf.WriteCollection(filepath.Join(dir, "named"), games, func(*sgf.Game, int) string { return ".." })
+	assert.Equal(t, err, nil, "problem writing collection")
+	files, err := ioutil.ReadDir(filepath.Join(dir, "named"))
+	assert.Equal(t, err, nil, "problem listing collection")
+	assert.Equal(t, len(files), len(games), "games overwritten")
+}
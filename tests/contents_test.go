@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableOfContents(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[pd];W[dp]N[Opening](;B[pp];W[dd])(;B[dd];W[pp]N[Alternative]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	toc := games[0].TableOfContents()
+	assert.Equal(t, len(toc), 2, "wrong number of entries")
+	assert.Equal(t, toc[0], sgf.TOCEntry{Path: []int{}, Name: "Opening", MoveNumber: 2}, "wrong first entry")
+	assert.Equal(t, toc[1], sgf.TOCEntry{Path: []int{1}, Name: "Alternative", MoveNumber: 4}, "wrong second entry")
+}
+
+func TestCommentTimeline(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[pd]C[First move];W[dp];B[pp];W[dd];B[fc]C[Approach](;W[cf])(;W[qf]C[Not this]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	timeline := games[0].CommentTimeline()
+	assert.Equal(t, timeline, []sgf.CommentEntry{
+		{MoveNumber: 1, Comment: "First move"},
+		{MoveNumber: 5, Comment: "Approach"},
+	}, "wrong timeline")
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDOT(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[dp](;W[ef];B[cf])(;W[ee]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	var buf bytes.Buffer
+	err = games[0].ToDOT(&buf)
+	assert.Equal(t, err, nil, "problem writing DOT")
+
+	dot := buf.String()
+	assert.Equal(t, strings.HasPrefix(dot, "digraph game {"), true, "not a digraph")
+	assert.Equal(t, strings.Count(dot, "[label="), 5, "wrong number of nodes")
+	assert.Equal(t, strings.Count(dot, " -> "), 4, "wrong number of edges")
+	assert.Equal(t, strings.Contains(dot, `n2 [label="W[ef]"]`), true, "missing variation node")
+	assert.Equal(t, strings.Contains(dot, "n1 -> n4"), true, "missing variation edge")
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+var filterCollection = "(;SZ[19]PB[Alice]PW[Bob];B[pd];W[dp];B[pp])" +
+	"(;SZ[9]PB[Carol]PW[Alice];B[ee])" +
+	"(;PB[Bob]PW[Carol];B[pd];W[dd])"
+
+func TestFilterByPlayer(t *testing.T) {
+	games := parse.Parse(filterCollection)
+
+	filtered := sgf.FilterGames(games, sgf.ByPlayer("alice"))
+	assert.Equal(t, len(filtered), 2, "wrong number of games")
+	assert.Equal(t, filtered[0], games[0], "wrong game")
+	assert.Equal(t, filtered[1], games[1], "wrong game")
+}
+
+func TestFilterByMinMoves(t *testing.T) {
+	games := parse.Parse(filterCollection)
+
+	filtered := sgf.FilterGames(games, sgf.ByMinMoves(2))
+	assert.Equal(t, len(filtered), 2, "wrong number of games")
+	assert.Equal(t, filtered[0], games[0], "wrong game")
+	assert.Equal(t, filtered[1], games[2], "wrong game")
+}
+
+func TestFilterByBoardSize(t *testing.T) {
+	games := parse.Parse(filterCollection)
+
+	filtered := sgf.FilterGames(games, sgf.ByBoardSize(19))
+	assert.Equal(t, len(filtered), 2, "wrong number of games")
+	assert.Equal(t, filtered[0], games[0], "wrong game")
+	assert.Equal(t, filtered[1], games[2], "SZ should default to 19")
+
+	filtered = sgf.FilterGames(games, sgf.ByBoardSize(9))
+	assert.Equal(t, len(filtered), 1, "wrong number of games")
+	assert.Equal(t, filtered[0], games[1], "wrong game")
+
+	games = parse.Parse("(;SZ[19:13];B[pd])(;SZ[13:19];B[dd])(;SZ[19];B[pp])")
+	filtered = sgf.FilterGames(games, sgf.ByBoardSize(19))
+	assert.Equal(t, len(filtered), 1, "rectangular boards should not match")
+	assert.Equal(t, filtered[0], games[2], "wrong game")
+
+	filtered = sgf.FilterGames(games, sgf.ByBoardDimensions(19, 13))
+	assert.Equal(t, len(filtered), 1, "wrong number of games")
+	assert.Equal(t, filtered[0], games[0], "wrong game")
+}
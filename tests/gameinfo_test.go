@@ -2,7 +2,10 @@ package tests
 
 import (
 	"testing"
+	"time"
 
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
 	"github.com/dhodges/sgfinfo/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -64,3 +67,190 @@ func TestGameInfoJsonKeys(t *testing.T) {
 	assert.Equal(t, "PlayerWhiteRank", keys[10], "")
 	assert.Equal(t, "Result",          keys[11], "")
 }
+
+func TestKomiNormalized(t *testing.T) {
+	komi, flipped := sgf.GameInfo{sgf.Komi: "6.5"}.KomiNormalized()
+	assert.Equal(t, komi, 6.5, "wrong komi")
+	assert.Equal(t, flipped, false, "komi should not be flipped")
+
+	komi, flipped = sgf.GameInfo{sgf.Komi: "-6.5"}.KomiNormalized()
+	assert.Equal(t, komi, 6.5, "wrong komi")
+	assert.Equal(t, flipped, true, "komi should be flipped")
+}
+
+func TestPlace(t *testing.T) {
+	place, ok := sgf.GameInfo{sgf.Place: "  Nihon Ki-in,\n   Tokyo "}.Place()
+	assert.Equal(t, ok, true, "place not found")
+	assert.Equal(t, place, "Nihon Ki-in, Tokyo", "place not normalized")
+
+	_, ok = sgf.GameInfo{}.Place()
+	assert.Equal(t, ok, false, "unexpected place")
+}
+
+func TestPlaceNormalizer(t *testing.T) {
+	sgf.PlaceNormalizer = func(place string) string {
+		if place == "Nihon Kiin" {
+			return "Nihon Ki-in, Tokyo"
+		}
+		return place
+	}
+	defer func() { sgf.PlaceNormalizer = nil }()
+
+	place, _ := sgf.GameInfo{sgf.Place: " Nihon Kiin "}.Place()
+	assert.Equal(t, place, "Nihon Ki-in, Tokyo", "place not normalized")
+}
+
+func TestMergeInfo(t *testing.T) {
+	games, err := parse.ParseString("(;PB[Go Seigen]PW[Honinbo Shusai];B[qc])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	game := games[0]
+
+	game.MergeInfo(map[string]string{"RE": "W+2", "PB": "Someone Else"}, false)
+	assert.Equal(t, game.GameInfo["RE"], "W+2", "missing result not added")
+	assert.Equal(t, game.GameInfo["PB"], "Go Seigen", "existing value replaced")
+
+	game.MergeInfo(map[string]string{"re": "B+R"}, false)
+	assert.Equal(t, game.GameInfo["RE"], "W+2", "existing result replaced")
+
+	game.MergeInfo(map[string]string{"RE": "B+R"}, true)
+	assert.Equal(t, game.GameInfo["RE"], "B+R", "existing result not overwritten")
+}
+
+func TestCopyright(t *testing.T) {
+	games, err := parse.ParseString("(;CP[(c) 2023 Example]PB[Alice];B[pd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].Copyright(), "(c) 2023 Example", "wrong copyright")
+
+	games, err = parse.ParseString(games[0].String())
+	assert.Equal(t, err, nil, "problem parsing serialized game")
+	assert.Equal(t, games[0].Copyright(), "(c) 2023 Example", "copyright lost in round-trip")
+}
+
+func TestNormalizeRank(t *testing.T) {
+	dan, ok := sgf.NormalizeRank("5d")
+	assert.Equal(t, ok, true, "5d not recognised")
+	kyu, ok := sgf.NormalizeRank("3k")
+	assert.Equal(t, ok, true, "3k not recognised")
+	pro, ok := sgf.NormalizeRank("7p")
+	assert.Equal(t, ok, true, "7p not recognised")
+
+	assert.True(t, kyu < dan, "3k should rank below 5d")
+	assert.True(t, dan < pro, "5d should rank below 7p")
+
+	grade, ok := sgf.NormalizeRank("9 dan")
+	assert.Equal(t, ok, true, "9 dan not recognised")
+	assert.Equal(t, grade, 9, "wrong grade")
+	grade, _ = sgf.NormalizeRank("3 Kyu?")
+	assert.Equal(t, grade, kyu, "wrong grade")
+
+	_, ok = sgf.NormalizeRank("strong")
+	assert.Equal(t, ok, false, "nonsense rank accepted")
+}
+
+func TestEffectiveKomi(t *testing.T) {
+	gi := sgf.GameInfo{"KM": "0.5", "HA": "4", "RU": "Chinese"}
+	assert.Equal(t, gi.EffectiveKomi(), 4.5, "handicap not compensated")
+
+	gi = sgf.GameInfo{"KM": "0.5", "HA": "4", "RU": "AGA"}
+	assert.Equal(t, gi.EffectiveKomi(), 3.5, "handicap not compensated")
+
+	gi = sgf.GameInfo{"KM": "0.5", "HA": "4", "RU": "Japanese"}
+	assert.Equal(t, gi.EffectiveKomi(), 0.5, "territory scoring adjusted")
+
+	gi = sgf.GameInfo{"KM": "7.5", "RU": "Chinese"}
+	assert.Equal(t, gi.EffectiveKomi(), 7.5, "even game adjusted")
+}
+
+func TestMainTimeLenient(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1]TM[3600];B[pd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	main, raw, ok := games[0].MainTimeLenient()
+	assert.Equal(t, ok, true, "time not read")
+	assert.Equal(t, main, time.Hour, "wrong main time")
+	assert.Equal(t, raw, "3600", "wrong raw value")
+
+	games, err = parse.ParseString("(;GM[1]TM[1h30m];B[pd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	main, raw, ok = games[0].MainTimeLenient()
+	assert.Equal(t, ok, true, "time not read")
+	assert.Equal(t, main, 90*time.Minute, "wrong main time")
+	assert.Equal(t, raw, "1h30m", "wrong raw value")
+
+	games, err = parse.ParseString("(;GM[1]TM[2 hours each];B[pd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	main, _, ok = games[0].MainTimeLenient()
+	assert.Equal(t, ok, true, "time not read")
+	assert.Equal(t, main, 2*time.Hour, "wrong main time")
+
+	games, err = parse.ParseString("(;GM[1]TM[unlimited];B[pd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	_, _, ok = games[0].MainTimeLenient()
+	assert.Equal(t, ok, false, "time read from text with no amount")
+}
+
+func TestTypedGameInfo(t *testing.T) {
+	tests := []struct {
+		info          sgf.GameInfo
+		width, height int
+		komi          float64
+		handicap      int
+		missing       bool
+	}{
+		{sgf.GameInfo{}, 19, 19, 0, 0, true},
+		{sgf.GameInfo{"SZ": "", "KM": " ", "HA": ""}, 19, 19, 0, 0, true},
+		{sgf.GameInfo{"SZ": "9", "KM": "7", "HA": "0"}, 9, 9, 7, 0, false},
+		{sgf.GameInfo{"SZ": "19:13", "KM": "0.5", "HA": "3"}, 19, 13, 0.5, 3, false},
+		{sgf.GameInfo{"SZ": " 13 ", "KM": "-6.5", "HA": "2"}, 13, 13, -6.5, 2, false},
+	}
+	for _, test := range tests {
+		expected := error(nil)
+		if test.missing {
+			expected = sgf.ErrPropertyMissing
+		}
+		width, height, err := test.info.BoardSize()
+		assert.Equal(t, err, expected, "wrong board size error")
+		assert.Equal(t, []int{width, height}, []int{test.width, test.height}, "wrong board size")
+
+		komi, err := test.info.Komi()
+		assert.Equal(t, err, expected, "wrong komi error")
+		assert.Equal(t, komi, test.komi, "wrong komi")
+
+		handicap, err := test.info.Handicap()
+		assert.Equal(t, err, expected, "wrong handicap error")
+		assert.Equal(t, handicap, test.handicap, "wrong handicap")
+	}
+
+	bad := sgf.GameInfo{"SZ": "big", "KM": "six", "HA": "2 stones"}
+	_, _, err := bad.BoardSize()
+	assert.NotEqual(t, err, nil, "expected a board size error")
+	_, err = bad.Komi()
+	assert.NotEqual(t, err, nil, "expected a komi error")
+	for _, size := range []string{"100", "0", "19:0", "53:19"} {
+		_, _, err = sgf.GameInfo{"SZ": size}.BoardSize()
+		assert.NotEqual(t, err, nil, "expected a board size error for SZ["+size+"]")
+	}
+	_, err = bad.Handicap()
+	assert.NotEqual(t, err, nil, "expected a handicap error")
+}
+
+func TestPlayers(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1]PB[Go Seigen]BR[5p]BT[Japan]PW[Honinbo Shusai];B[qd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	black := games[0].Black()
+	assert.Equal(t, black.Name(), "Go Seigen", "wrong name")
+	assert.Equal(t, black.Rank(), "5p", "wrong rank")
+	assert.Equal(t, black.Team(), "Japan", "wrong team")
+	grade, ok := black.Grade()
+	assert.Equal(t, ok, true, "rank not read")
+	assert.Equal(t, grade, 14, "wrong grade")
+
+	white := games[0].White()
+	assert.Equal(t, white.Name(), "Honinbo Shusai", "wrong name")
+	assert.Equal(t, white.Rank(), "", "missing rank not empty")
+	assert.Equal(t, white.Team(), "", "missing team not empty")
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashGameIgnoresComments(t *testing.T) {
+	hash1, err := parse.HashGame(strings.NewReader("(;GM[1]PB[Alice];B[dp]C[good move];W[pd](;B[cd])(;B[dd]))"))
+	assert.Equal(t, err, nil, "problem hashing game")
+
+	hash2, err := parse.HashGame(strings.NewReader("(;GM[1]PB[Bob]C[a game];B[dp];W[pd]C[hmm](;B[cd]C[joseki])(;B[dd]))"))
+	assert.Equal(t, err, nil, "problem hashing game")
+
+	assert.Equal(t, hash1, hash2, "hashes should match")
+}
+
+func TestHashGameDiffersOnMoves(t *testing.T) {
+	hash1, err := parse.HashGame(strings.NewReader("(;GM[1];B[dp];W[pd])"))
+	assert.Equal(t, err, nil, "problem hashing game")
+
+	hash2, err := parse.HashGame(strings.NewReader("(;GM[1];B[dp];W[dd])"))
+	assert.Equal(t, err, nil, "problem hashing game")
+
+	assert.NotEqual(t, hash1, hash2, "hashes should differ")
+}
+
+func TestHashGameStreams(t *testing.T) {
+	gameStr := "(;GM[1]C[a \\] (tricky) comment];B[dp];W[pd](;B[cd])(;B[dd]))"
+	hash1, err := parse.HashGame(strings.NewReader(gameStr))
+	assert.Equal(t, err, nil, "problem hashing game")
+
+	hash2, err := parse.HashGame(iotest.OneByteReader(strings.NewReader(gameStr)))
+	assert.Equal(t, err, nil, "problem hashing game a byte at a time")
+	assert.Equal(t, hash1, hash2, "hashes should match")
+
+	hash3, err := parse.HashGame(strings.NewReader("(;GM[1];B[dp];W[pd](;B[cd])(;B[dd]))"))
+	assert.Equal(t, err, nil, "problem hashing game")
+	assert.Equal(t, hash1, hash3, "parentheses in a comment are not structure")
+
+	_, err = parse.HashGame(strings.NewReader("(;GM[1];B[dp];W[p"))
+	assert.NotEqual(t, err, nil, "expected an error for truncated input")
+
+	readErr := errors.New("disk on fire")
+	_, err = parse.HashGame(io.MultiReader(strings.NewReader("(;GM[1];B[dp]"), iotest.ErrReader(readErr)))
+	assert.Equal(t, err, readErr, "read error not returned")
+}
+
+func TestHashGameCountsNodes(t *testing.T) {
+	hash1, err := parse.HashGame(strings.NewReader("(;GM[1];B[dp];W[pd])"))
+	assert.Equal(t, err, nil, "problem hashing game")
+	hash2, err := parse.HashGame(strings.NewReader("(;GM[1];B[dp]W[pd])"))
+	assert.Equal(t, err, nil, "problem hashing game")
+	assert.NotEqual(t, hash1, hash2, "hashes should differ on node boundaries")
+}
+
+func TestHashGameErrorPosition(t *testing.T) {
+	// long enough for the lexer to read it in several pieces
+	gameStr := "(;GM[1]\r\n" + strings.Repeat(";B[dp]C[a comment]\r\n;W[pd]\n", 500) + ";B[dp]C]oops)"
+	_, err := parse.HashGame(strings.NewReader(gameStr))
+	_, expected := parse.ParseString(gameStr)
+	assert.NotEqual(t, err, nil, "expected an error")
+	assert.Contains(t, expected.Error(), strconv.Quote(err.Error()))
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+var koSetup = "(;SZ[9]AB[ba][ab][bc]AW[ca][bb][db][cc]"
+
+func TestKoThreatResolved(t *testing.T) {
+	games, err := parse.ParseString(koSetup + ";B[cb];W[gg];B[hh];W[bb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	assert.Equal(t, game.KoThreatResolved(4), true, "recapture after a threat should be legal")
+	assert.Equal(t, game.KoThreatResolved(2), false, "threat is not a recapture")
+}
+
+func TestKoImmediateRecapture(t *testing.T) {
+	games, err := parse.ParseString(koSetup + ";B[cb];W[bb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	assert.Equal(t, games[0].KoThreatResolved(2), false, "immediate recapture should be illegal")
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenukis(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd];W[qf];B[qh];W[pf];B[dp];W[cn])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	assert.Equal(t, games[0].Tenukis(10), []int{5}, "wrong tenukis")
+}
+
+func TestPasses(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd];W[dp];B[pp];W[tt];B[dd];W[];B[])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	assert.Equal(t, games[0].Passes(), []int{4, 6, 7}, "wrong passes")
+}
+
+func TestMoveCountByColor(t *testing.T) {
+	gameStr := "(;SZ[19]"
+	for i := 0; i < 31; i++ {
+		color := "B"
+		if i%2 == 1 {
+			color = "W"
+		}
+		gameStr += fmt.Sprintf(";%s[%c%c]", color, 'a'+i%19, 'a'+i/19)
+	}
+	gameStr += ")"
+
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	black, white := games[0].MoveCountByColor()
+	assert.Equal(t, black, 16, "wrong black move count")
+	assert.Equal(t, white, 15, "wrong white move count")
+
+	games, err = parse.ParseString("(;SZ[19];B[pd];W[];B[dp];W[tt];B[])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	black, white = games[0].MoveCountByColor()
+	assert.Equal(t, []int{black, white}, []int{3, 2}, "passes should be counted")
+	black, white = games[0].MoveCountByColorExcludingPasses()
+	assert.Equal(t, []int{black, white}, []int{2, 0}, "passes should be left out")
+}
+
+func TestOpeningFingerprint(t *testing.T) {
+	games := parse.Parse("(;SZ[19];B[pd];W[dp];B[qp];W[dd];B[fq])" +
+		"(;SZ[19];B[pp];W[dd];B[dq];W[pd];B[cf])" +
+		"(;SZ[19];B[pd];W[dp];B[pp];W[dd];B[fq])")
+
+	assert.Equal(t, games[0].OpeningFingerprint(5), games[1].OpeningFingerprint(5), "rotated openings should match")
+	assert.NotEqual(t, games[0].OpeningFingerprint(5), games[2].OpeningFingerprint(5), "different openings should differ")
+	assert.Equal(t, games[0].OpeningFingerprint(2), games[2].OpeningFingerprint(2), "shared first moves should match")
+}
+
+func TestLengths(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];AB[dd][pp];B[pd];W[dp];C[setup done]AW[qq];B[qp])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	nodes, moves := games[0].Lengths()
+	assert.Equal(t, nodes, 6, "wrong node count")
+	assert.Equal(t, moves, 3, "wrong move count")
+}
+
+func TestMoveSummaryLine(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19]PB[Alice]PW[Bob]RE[B+R];B[pd];W[dp];B[pp](;W[dd])(;W[dc];B[ce]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].MoveSummaryLine(), "19x19 B+R 4mv PB=Alice PW=Bob", "wrong summary")
+
+	games, err = parse.ParseString("(;SZ[9];B[ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].MoveSummaryLine(), "9x9 ? 1mv PB=? PW=?", "wrong summary")
+}
+
+func TestBiggestSwing(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd]V[0.5];W[dp]V[1];B[pp]V[0.5];W[ss]V[-12.5];B[dd]V[-12])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	moveNum, delta := games[0].BiggestSwing()
+	assert.Equal(t, moveNum, 4, "wrong move")
+	assert.Equal(t, delta, -13.0, "wrong swing")
+
+	games, err = parse.ParseString("(;SZ[19]PB[Alice])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	moveNum, _ = games[0].BiggestSwing()
+	assert.Equal(t, moveNum, 0, "game has no moves")
+}
+
+func TestScoreDeltas(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd]V[0.5];W[dp]V[1];B[pp];W[ss]V[-12.5];B[dd]V[-12])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	deltas := games[0].ScoreDeltas()
+	assert.Equal(t, deltas, []float64{0.5, -13.5, 0.5}, "wrong deltas")
+	sum := 0.0
+	for _, delta := range deltas {
+		sum += delta
+	}
+	assert.Equal(t, sum, -12.0-0.5, "deltas should sum to the last estimate less the first")
+
+	games, err = parse.ParseString("(;SZ[19];B[pd]V[0.5])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, len(games[0].ScoreDeltas()), 0, "one estimate has no deltas")
+}
+
+func TestAnnotatedMoves(t *testing.T) {
+	gameStr := "(;GM[1]C[root]"
+	for n := 1; n <= 14; n++ {
+		color := "B"
+		if n%2 == 0 {
+			color = "W"
+		}
+		gameStr += fmt.Sprintf(";%s[%c%c]", color, 'a'+n, 'c')
+		switch n {
+		case 5, 12:
+			gameStr += "C[note]"
+		case 8:
+			gameStr += "TR[aa]"
+		}
+	}
+	games, err := parse.ParseString(gameStr + ")")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].AnnotatedMoves(), []int{5, 8, 12}, "wrong annotated moves")
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeEqual(t *testing.T) {
+	gameStr := "(;GM[1];B[dp](;W[ef];B[cf](;W[fc];B[dc])(;W[rl]AB[aa][bb]));W[ee])"
+
+	games1, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	games2, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	assert.Equal(t, games1[0].GameTree.Equal(games2[0].GameTree), true, "subtrees should be equal")
+}
+
+func TestNodeNotEqual(t *testing.T) {
+	games1, err := parse.ParseString("(;GM[1];B[dp](;W[ef];B[cf](;W[fc];B[dc])(;W[rl]AB[aa][bb]));W[ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	games2, err := parse.ParseString("(;GM[1];B[dp](;W[ef];B[cf](;W[fc];B[dc])(;W[rl]AB[aa][cc]));W[ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	assert.Equal(t, games1[0].GameTree.Equal(games2[0].GameTree), false, "subtrees should differ")
+}
+
+func TestTrimEmpty(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[aa];W[bb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	game.GameTree.Next.NewNode()
+	assert.Equal(t, game.String(), "(;GM[1];B[aa];W[bb];)", "empty node not added")
+
+	game.TrimEmpty()
+	assert.Equal(t, game.String(), "(;GM[1];B[aa];W[bb])", "trailing empty node not removed")
+}
+
+func TestTrimEmptyKeepsStructuralNodes(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[aa];W[bb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	last := game.GameTree.Next
+	game.GameTree.Next = new(sgf.Node)
+	game.GameTree.Next.Next = last
+	last.NewNode()
+
+	game.TrimEmpty()
+	assert.Equal(t, game.String(), "(;GM[1];B[aa];;W[bb])", "structural empty node removed")
+}
+
+func TestDedupeVariations(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[dp](;W[ef];B[cf])(;W[fc])(;W[ef];B[cf]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	game.DedupeVariations()
+	assert.Equal(t, len(game.GameTree.Variations), 2, "duplicate variation not removed")
+	assert.Equal(t, game.String(), "(;GM[1];B[dp](;W[ef];B[cf])(;W[fc]))", "wrong variations kept")
+}
+
+func TestPropertyValues(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[pd]AB[dd][pp][dp]LB[aa:A][bb:B])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	node := games[0].GameTree
+	assert.Equal(t, node.PropertyValues("AB"), []string{"dd", "pp", "dp"}, "wrong setup stones")
+	assert.Equal(t, node.PropertyValues("lb"), []string{"aa:A", "bb:B"}, "wrong labels")
+	assert.Equal(t, node.PropertyValues("B"), []string{"pd"}, "wrong move")
+	assert.Equal(t, len(node.PropertyValues("AW")), 0, "unexpected values")
+}
+
+func TestNodeMove(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1];B[pd];W[]C[pass];AB[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	color, point, isPass := games[0].GameTree.Move()
+	assert.Equal(t, color, "B", "wrong colour")
+	assert.Equal(t, point, sgf.Point{X: 'p', Y: 'd'}, "wrong point")
+	assert.Equal(t, isPass, false, "move taken as a pass")
+
+	color, _, isPass = games[0].GameTree.Next.Move()
+	assert.Equal(t, color, "W", "wrong colour")
+	assert.Equal(t, isPass, true, "pass not recognised")
+
+	color, _, _ = games[0].GameTree.Next.Next.Move()
+	assert.Equal(t, color, "", "setup node has a move")
+}
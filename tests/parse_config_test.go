@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLenientRepairsCP1252(t *testing.T) {
+	games := parse.ParseWithConfig("(;GM[1];B[aa]C[it\x92s caf\xe9 time])", parse.ParserConfig{Lenient: true})
+
+	game := games[0]
+	assert.Equal(t, len(game.Errors), 0, "unexpected errors")
+	assert.Equal(t, len(game.Warnings), 1, "repair not recorded")
+	assert.Equal(t, game.GameTree.Properties[0].Value, "it’s café time", "comment not repaired")
+}
+
+func TestLenientRepairsCP1252DespiteCharset(t *testing.T) {
+	games := parse.ParseWithConfig("(;CA[UTF-8];B[aa]C[don\x92t \x96 ever])", parse.ParserConfig{Lenient: true})
+
+	game := games[0]
+	assert.Equal(t, len(game.Warnings), 1, "repair not recorded")
+	assert.Equal(t, game.Warnings[0].Error(), "property C: repaired Windows-1252 text", "wrong warning")
+	assert.Equal(t, game.GameTree.Properties[0].Value, "don\u2019t \u2013 ever", "comment not repaired")
+}
+
+func TestLenientRepairsCP1252WithEscapes(t *testing.T) {
+	games := parse.ParseWithConfig("(;GM[1];B[aa]C[it\x92s \\] ok])", parse.ParserConfig{Lenient: true})
+
+	game := games[0]
+	assert.Equal(t, len(game.Warnings), 1, "repair not recorded")
+	assert.Equal(t, game.GameTree.Properties[0].Value, "it’s ] ok", "comment not repaired")
+}
+
+func TestStrictLeavesCP1252(t *testing.T) {
+	games := parse.Parse("(;GM[1];B[aa]C[it\x92s])")
+
+	game := games[0]
+	assert.Equal(t, len(game.Warnings), 0, "unexpected warning")
+	assert.Equal(t, game.GameTree.Properties[0].Value, "it\x92s", "comment should be untouched")
+}
+
+func TestLenientJoinsSplitText(t *testing.T) {
+	games := parse.ParseWithConfig("(;GC[first ][second];B[aa]C[part one, ][part two])", parse.ParserConfig{Lenient: true})
+
+	game := games[0]
+	assert.Equal(t, len(game.Errors), 0, "unexpected errors")
+	assert.Equal(t, len(game.Warnings), 2, "joins not recorded")
+	assert.Equal(t, game.GameInfo[sgf.GameComment], "first second", "game comment not joined")
+	assert.Equal(t, len(game.GameTree.Properties), 1, "comment not joined")
+	assert.Equal(t, game.GameTree.Properties[0].Value, "part one, part two", "comment not joined")
+}
+
+func TestTrimSimpleText(t *testing.T) {
+	gameStr := "(;PB[ Alice ]PW[Bob  ]GC[ indented ];B[aa])"
+
+	game := parse.ParseWithConfig(gameStr, parse.ParserConfig{TrimSimpleText: true})[0]
+	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], "Alice", "black name not trimmed")
+	assert.Equal(t, game.GameInfo[sgf.PlayerWhiteName], "Bob", "white name not trimmed")
+	assert.Equal(t, game.GameInfo[sgf.GameComment], " indented ", "text should not be trimmed")
+
+	game = parse.Parse(gameStr)[0]
+	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], " Alice ", "name trimmed by default")
+}
+
+func TestMovesOnly(t *testing.T) {
+	gameStr := "(;GM[1]PB[Alice]C[root comment];B[pd]C[first]TR[pd];W[dp]AB[aa](;B[pp]C[main])(;B[dd]LB[dd:A]))"
+	config := parse.ParserConfig{LexOptions: parse.LexOptions{MovesOnly: true}}
+
+	game := parse.ParseWithConfig(gameStr, config)[0]
+	assert.Equal(t, len(game.Errors), 0, "unexpected errors")
+	assert.Equal(t, game.String(), "(;C[root comment]GM[1]PB[Alice];B[pd];W[dp]AB[aa](;B[pp])(;B[dd]))", "wrong tree kept")
+
+	// the skipped values are never lexed into items, so a heavily annotated
+	// game allocates far less
+	annotated := "(;GM[1]" + strings.Repeat(";B[pd]C[a comment]TR[pd]LB[pd:A];W[dp]C[another\\]one]", 200) + ")"
+	full := testing.AllocsPerRun(10, func() { parse.Parse(annotated) })
+	moves := testing.AllocsPerRun(10, func() { parse.ParseWithConfig(annotated, config) })
+	assert.True(t, moves < full/2, "moves-only parse should allocate less")
+}
+
+func TestParseBestEffort(t *testing.T) {
+	game := parse.ParseBestEffort("(;PB[Alice]PW[Bob]DT[2023-01-02];B[pd];W[dp];B[p")
+	assert.Equal(t, len(game.Errors), 0, "errors should be warnings")
+	assert.Equal(t, len(game.Warnings), 1, "truncation not recorded")
+	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], "Alice", "game info lost")
+	assert.Equal(t, game.GameInfo[sgf.Date], "2023-01-02", "game info lost")
+	assert.Equal(t, game.GameTree.Next.Point.String(), "W[dp]", "moves lost")
+
+	game = parse.ParseBestEffort("(;PB[Alice];B[pd]W;W[dp];B[dd])")
+	assert.Equal(t, len(game.Warnings), 1, "error not recorded")
+	assert.Equal(t, game.GameTree.Next.Next.Point.String(), "B[dd]", "moves after the error lost")
+
+	game = parse.ParseBestEffort("not sgf at all")
+	assert.Equal(t, len(game.GameInfo), 0, "expected an empty game")
+}
+
+var nestedGameStr = "(;PB[Alice]PW[Bob];B[pd];W[dp](;B[pp])(;PB[Carol]PW[Dave];B[dd];W[pp]))"
+
+func TestSplitNestedGames(t *testing.T) {
+	games := parse.ParseWithConfig(nestedGameStr, parse.ParserConfig{Lenient: true, SplitNestedGames: true})
+	assert.Equal(t, len(games), 2, "nested game not split out")
+	assert.Equal(t, len(games[0].Errors), 0, "unexpected errors")
+	assert.Equal(t, len(games[0].Warnings), 1, "split not recorded")
+	assert.Equal(t, games[0].String(), "(;PB[Alice]PW[Bob];B[pd];W[dp](;B[pp]))", "wrong outer game")
+	assert.Equal(t, games[1].String(), "(;PB[Carol]PW[Dave];B[dd];W[pp])", "wrong nested game")
+}
+
+func TestKeepNestedGames(t *testing.T) {
+	games := parse.ParseWithConfig(nestedGameStr, parse.ParserConfig{Lenient: true})
+	assert.Equal(t, len(games), 1, "nested game split out")
+	assert.Equal(t, len(games[0].Warnings), 1, "nested game not recorded")
+	assert.Equal(t, len(games[0].GameTree.Next.Variations), 2, "nested game not kept as a variation")
+}
+
+func TestRecoverErrors(t *testing.T) {
+	input := "(;GM[1];B[aa]C]oops;W[bb];B[cc]x;W[dd])"
+
+	games := parse.Parse(input)
+	assert.Equal(t, len(games[0].Errors), 1, "strict parse should stop at the first error")
+	assert.Equal(t, games[0].NodeCount(), 2, "strict parse read past the error")
+
+	games = parse.ParseWithConfig(input, parse.ParserConfig{RecoverErrors: true})
+	game := games[0]
+	assert.Equal(t, len(game.Errors), 2, "errors not all recorded")
+	assert.Equal(t, game.NodeCount(), 5, "nodes after the errors lost")
+	assert.Equal(t, game.GameTree.Next.Next.Next.Point.Name, "W", "wrong last move")
+}
+
+func TestRecoverParserErrors(t *testing.T) {
+	input := "(;GM[1];B[aa]);W[bb](;GM[1];B[cc]]x;W[dd](;)"
+
+	games := parse.ParseWithConfig(input, parse.ParserConfig{RecoverErrors: true})
+	assert.Equal(t, len(games), 2, "wrong number of games")
+	assert.Equal(t, len(games[0].Errors), 1, "content after the game not reported")
+	assert.Equal(t, len(games[1].Errors), 1, "error in the second game not reported")
+	assert.Equal(t, games[1].NodeCount(), 3, "nodes after the errors lost")
+
+	games = parse.ParseWithConfig("(;)junk;B[aa]", parse.ParserConfig{RecoverErrors: true})
+	assert.Equal(t, len(games[0].Errors), 3, "errors not all reported")
+}
+
+func TestMaxErrors(t *testing.T) {
+	input := "(;GM[1]" + strings.Repeat(";B[aa]]x", 200) + ")"
+
+	game := parse.ParseWithConfig(input, parse.ParserConfig{RecoverErrors: true, MaxErrors: 5})[0]
+	assert.Equal(t, len(game.Errors), 6, "parsing did not stop at MaxErrors")
+	assert.Contains(t, game.Errors[5].Error(), "too many errors")
+	assert.Equal(t, game.NodeCount(), 6, "nodes before the cap lost")
+
+	game = parse.ParseWithConfig(input, parse.ParserConfig{RecoverErrors: true})[0]
+	assert.Equal(t, len(game.Errors), parse.DefaultMaxErrors+1, "default cap not applied")
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFileModTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgfinfo")
+	assert.Equal(t, err, nil, "problem creating temp dir")
+	defer os.RemoveAll(dir)
+
+	fpath := filepath.Join(dir, "game.sgf")
+	err = ioutil.WriteFile(fpath, []byte("(;GM[1]PB[Alice];B[dp])"), 0644)
+	assert.Equal(t, err, nil, "problem writing temp file")
+
+	mtime := time.Date(2014, 7, 6, 12, 0, 0, 0, time.UTC)
+	err = os.Chtimes(fpath, mtime, mtime)
+	assert.Equal(t, err, nil, "problem setting file times")
+
+	games, err := parse.ParseFile(fpath)
+	assert.Equal(t, err, nil, "problem parsing file")
+	assert.Equal(t, games[0].FileModTime().Equal(mtime), true, "wrong modification time")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestParseReader(t *testing.T) {
+	games, err := parse.ParseReader(strings.NewReader("(;GM[1]PB[Alice];B[dp])"))
+	assert.Equal(t, err, nil, "problem parsing reader")
+	assert.Equal(t, games[0].GameInfo["PB"], "Alice", "wrong game")
+
+	_, err = parse.ParseReader(failingReader{})
+	assert.Equal(t, err.Error(), "connection reset", "read error not returned as it is")
+}
+
+func TestParseFileErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgfinfo")
+	assert.Equal(t, err, nil, "problem creating temp dir")
+	defer os.RemoveAll(dir)
+
+	fpath := filepath.Join(dir, "missing.sgf")
+	_, err = parse.ParseFile(fpath)
+	assert.NotEqual(t, err, nil, "expected an error")
+	assert.Contains(t, err.Error(), fpath, "file name not in open error")
+
+	fpath = filepath.Join(dir, "broken.sgf")
+	err = ioutil.WriteFile(fpath, []byte("(;GM[1]PB[Alice];B[dp"), 0644)
+	assert.Equal(t, err, nil, "problem writing temp file")
+
+	_, err = parse.ParseFile(fpath)
+	assert.NotEqual(t, err, nil, "expected an error")
+	assert.Contains(t, err.Error(), fpath+": problems parsing sgf", "file name not in parse error")
+}
@@ -40,3 +40,42 @@ func TestParsingFullGameInfo(t *testing.T) {
 	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], "Go Seigen",      "invalid property")
 	assert.Equal(t, game.GameInfo[sgf.PlayerWhiteName], "Honinbo Shusai", "invalid property")
 }
+
+func TestDecodedCharsetRoundTrip(t *testing.T) {
+	games, err := parse.ParseString("(;CA[ISO-8859-1]PB[Jos\xe9];B[aa])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameInfo["CA"], "UTF-8", "charset not updated after decoding")
+
+	reparsed, err := parse.ParseString(games[0].String())
+	assert.Equal(t, err, nil, "problem parsing written game")
+	assert.Equal(t, reparsed[0].GameInfo["PB"], "José", "text corrupted by a round trip")
+}
+
+func TestParseDecodesCharset(t *testing.T) {
+	games, err := parse.ParseString("(;CA[ISO-8859-1]PB[Jos\xe9]PW[Fran\xe7ois];B[pd]C[tr\xe8s bien])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameInfo["PB"], "José", "player name not decoded")
+	assert.Equal(t, games[0].GameInfo["PW"], "François", "player name not decoded")
+	assert.Equal(t, games[0].GameTree.PropertyValues("C"), []string{"très bien"}, "comment not decoded")
+
+	games, err = parse.ParseString("(;CA[Shift_JIS]PW[\x96\x7b\x88\xf6\x96\x56];B[pd]C[\x88\xcd\x8c\xe9](;W[dd]C[\x88\xcd\x8c\xe9]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameInfo["PW"], "本因坊", "player name not decoded")
+	assert.Equal(t, games[0].GameTree.PropertyValues("C"), []string{"囲碁"}, "comment not decoded")
+	assert.Equal(t, games[0].GameTree.Variations[0].PropertyValues("C"), []string{"囲碁"}, "variation comment not decoded")
+
+	games, err = parse.ParseString("(;CA[Shift_JIS]PB[\x95\x5c]PW[\x83\x5d];B[aa];W[bb])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameInfo["PB"], "表", "name ending in a 0x5c byte misread")
+	assert.Equal(t, games[0].GameInfo["PW"], "ゾ", "name ending in a 0x5d byte misread")
+	assert.Equal(t, games[0].NodeCount(), 3, "game tree lost")
+
+	games = parse.Parse("(;CA[Shift_JIS]PB[\x95\x5c];B[aa])(;CA[ISO-8859-1]PB[Jos\xe9];B[aa])")
+	assert.Equal(t, len(games[0].Errors), 1, "mixed multibyte charset not reported")
+	assert.Equal(t, games[0].Errors[0].Error(), "CA[Shift_JIS]: can't be read in a file mixing charsets", "wrong error")
+	assert.Equal(t, games[1].GameInfo["PB"], "José", "player name not decoded")
+
+	games = parse.Parse("(;CA[Klingon]PB[Alice];B[pd])")
+	assert.Equal(t, len(games[0].Errors), 1, "unknown charset not reported")
+	assert.Equal(t, games[0].Errors[0].Error(), "CA[Klingon]: unknown charset", "wrong error")
+}
@@ -1,7 +1,10 @@
 package tests
 
 import (
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
   "github.com/dhodges/sgfinfo/parse"
   "github.com/stretchr/testify/assert"
@@ -22,7 +25,7 @@ func TestParsingGameTree(t *testing.T) {
 	assert.Equal(t, err, nil, "problem parsing gametree string")
 
 	game := games[0]
-	assert.Equal(t, game.NodeCount(), 6, "game tree is incorrect")
+	assert.Equal(t, game.NodeCount(), 7, "game tree is incorrect")
 
 	node := game.GameTree
 	assert.Equal(t, node.Point.String(), "B[qc]", "1st node move is incorrect")
@@ -48,11 +51,131 @@ func TestParsingFullGameTree(t *testing.T) {
 	assert.Equal(t, err, nil, "problem loading fixture")
 
 	game := games[0]
-	assert.Equal(t, game.NodeCount(), 7, "wrong number of game nodes")
+	assert.Equal(t, game.NodeCount(), 8, "wrong number of game nodes")
 
 	node, err := game.NthNode(7)
 	assert.Equal(t, err, nil, "problem getting node")
 
 	assert.Equal(t, node.Point.String(),  "B[hd]", "wrong node")
 	assert.Equal(t, len(node.Variations), 3,       "wrong number of variations")
+
+	last, err := game.NthNode(game.NodeCount() - 1)
+	assert.Equal(t, err, nil, "problem getting last node")
+	assert.Equal(t, last, node, "NthNode should leave out the root")
+	_, err = game.NthNode(game.NodeCount())
+	assert.NotEqual(t, err, nil, "expected an error past the last node")
+}
+
+func TestParsingEmptyGame(t *testing.T) {
+	games, err := parse.ParseString("(;)")
+	assert.Equal(t, err, nil, "problem parsing empty game")
+	assert.Equal(t, len(games), 1, "wrong number of games")
+
+	game := games[0]
+	assert.Equal(t, len(game.Errors), 0, "unexpected parse errors")
+	assert.Equal(t, game.NodeCount(), 1, "empty game should have just the root")
+	nodes, moves := game.Lengths()
+	assert.Equal(t, nodes, 1, "wrong main line length")
+	assert.Equal(t, moves, 0, "wrong move count")
+}
+
+func TestParsingFF3Properties(t *testing.T) {
+	games, err := parse.ParseString("(;FF[3]SZ[19];B[pd]M[qc];W[dp]L[cq][dq])")
+	assert.Equal(t, err, nil, "problem parsing FF[3] game")
+	assert.Equal(t, games[0].GameTreeString(), ";B[pd]MA[qc];W[dp]LB[cq:a][dq:b]", "FF[3] properties not translated")
+
+	games, err = parse.ParseString("(;FF[3]SZ[19]L[aa][bb]M[cc];B[pd]EX[dp]EL[12];W[dp])")
+	assert.Equal(t, err, nil, "problem parsing FF[3] game")
+	assert.Equal(t, games[0].String(), "(;FF[3]SZ[19]LB[aa:a][bb:b]MA[cc];B[pd]EX[dp]EL[12];W[dp])", "FF[3] root properties not translated")
+	assert.Equal(t, len(games[0].Warnings), 2, "properties without FF[4] equivalents not reported")
+
+	games, err = parse.ParseString("(;FF[4]SZ[19];B[pd]M[qc])")
+	assert.Equal(t, err, nil, "problem parsing FF[4] game")
+	assert.Equal(t, games[0].GameTreeString(), ";B[pd]M[qc]", "FF[4] properties translated")
+}
+
+func TestIsLinear(t *testing.T) {
+	games, err := parse.ParseString(gameTreeString)
+	assert.Equal(t, err, nil, "problem parsing gametree string")
+	assert.Equal(t, games[0].IsLinear(), true, "game has no variations")
+
+	games, err = parse.ParseString("(;GM[1];B[pd](;W[dp])(;W[dd]))")
+	assert.Equal(t, err, nil, "problem parsing gametree string")
+	assert.Equal(t, games[0].IsLinear(), false, "game has variations")
+}
+
+func TestParsingEscapedBracket(t *testing.T) {
+	games, err := parse.ParseString(`(;GM[1];B[pd]C[see move 5\] then resign];W[dp])`)
+	assert.Equal(t, err, nil, "problem parsing escaped bracket")
+	assert.Equal(t, games[0].GameTree.Properties[0].Value, "see move 5] then resign", "escape not removed")
+	assert.Equal(t, games[0].NodeCount(), 3, "value ended early")
+}
+
+func TestParsingErrorsDoNotLeakGoroutines(t *testing.T) {
+	// the parser gives up at the node after the game while the lexer
+	// still has the rest to send
+	input := "(;GM[1];B[aa]);W[bb]" + strings.Repeat(";B[cc];W[dd]", 50)
+	games := parse.Parse(input)
+	assert.True(t, len(games[0].Errors) > 0, "input should stop the parser early")
+
+	before := runtime.NumGoroutine()
+	for n := 0; n < 2000; n++ {
+		parse.Parse(input)
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, runtime.NumGoroutine() < before+10, "lexer goroutines leaked")
+}
+
+func TestParsingNodesAfterVariations(t *testing.T) {
+	// a node may carry on after its variations, as the serializer writes it
+	gameStr := "(;GM[1](;B[aa]);W[bb];B[cc])"
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "nodes after variations should be accepted")
+	assert.Equal(t, games[0].String(), gameStr, "nodes after variations not written back")
+}
+
+func TestParseKeepsLineBreaksInComments(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1]\n;B[pd]\nC[line one\nline two]\n;W[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameTree.PropertyValues("C"), []string{"line one\nline two"}, "line break lost")
+	assert.Equal(t, games[0].NodeCount(), 3, "wrong node count")
+}
+
+func TestParseWhitespaceBeforeValues(t *testing.T) {
+	spaced, err := parse.ParseString("(;GM [1]\n;B [aa] W\n[bb]\nC  [a comment] AB [cc]\n  [dd])")
+	assert.Equal(t, err, nil, "problem parsing spaced game string")
+	compact, err := parse.ParseString("(;GM[1];B[aa]W[bb]C[a comment]AB[cc][dd])")
+	assert.Equal(t, err, nil, "problem parsing compact game string")
+
+	assert.Equal(t, spaced[0].String(), compact[0].String(), "spacing changed the game")
+	assert.Equal(t, spaced[0].NodeCount(), 2, "wrong node count")
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	games := parse.Parse("(;GM[1];B[aa])\n\n  ;W[bb]")
+	assert.Equal(t, len(games[0].Errors), 1, "error not reported")
+	assert.Contains(t, games[0].Errors[0].Error(), "line 3, col 3: node or property outside a game tree", "wrong error position")
+}
+
+func TestParseContentAfterEmptyGame(t *testing.T) {
+	for _, input := range []string{"(;)B[aa]", "(;);B[aa]"} {
+		games := parse.Parse(input)
+		assert.Equal(t, len(games), 1, "wrong number of games")
+		assert.Equal(t, len(games[0].Errors), 1, "stray content not reported for "+input)
+	}
+}
+
+func TestParseRootVariations(t *testing.T) {
+	games, err := parse.ParseString("(;(;B[aa];W[bb])(;B[cc]))")
+	assert.Equal(t, err, nil, "problem parsing game with an empty root")
+	game := games[0]
+	assert.Equal(t, game.NodeCount(), 3, "wrong node count")
+	assert.Equal(t, game.String(), "(;(;B[aa];W[bb])(;B[cc]))", "root variations not written back")
+
+	games, err = parse.ParseString("(;GM[1](;B[aa])(;B[cc];W[dd]))")
+	assert.Equal(t, err, nil, "problem parsing game with root variations")
+	assert.Equal(t, games[0].String(), "(;GM[1](;B[aa])(;B[cc];W[dd]))", "root variations not written back")
+	node, err := games[0].NodeAtPath([]int{1})
+	assert.Equal(t, err, nil, "problem following path")
+	assert.Equal(t, node.Point.String(), "W[dd]", "wrong variation")
 }
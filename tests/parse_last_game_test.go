@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/fixtures"
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLastGameInfo(t *testing.T) {
+	archive := "(;PB[Alice]PW[Bob];B[dp];W[pd])\n" +
+		"(;PB[Carol]PW[Dave];B[pp](;W[dd])(;W[dp]C[not (; a game]))\n"
+
+	gi, err := parse.ParseLastGameInfo(strings.NewReader(archive), int64(len(archive)))
+	assert.Equal(t, err, nil, "problem parsing last game")
+	assert.Equal(t, gi[sgf.PlayerBlackName], "Carol", "wrong game found")
+	assert.Equal(t, gi[sgf.PlayerWhiteName], "Dave", "wrong game found")
+}
+
+func TestParseLastGameInfoLongGame(t *testing.T) {
+	archive := "(;PB[Alice];B[dp])" +
+		"(;PB[Carol]C[" + strings.Repeat("a long comment ", 1000) + "];B[pp])"
+
+	gi, err := parse.ParseLastGameInfo(strings.NewReader(archive), int64(len(archive)))
+	assert.Equal(t, err, nil, "problem parsing last game")
+	assert.Equal(t, gi[sgf.PlayerBlackName], "Carol", "wrong game found")
+}
+
+func TestParseLastGameInfoCollection(t *testing.T) {
+	fixture, err := fixtures.Sgf("honinbo.sgf")
+	assert.Equal(t, err, nil, "problem loading fixture")
+
+	games := parse.Parse(fixture)
+	gi, err := parse.ParseLastGameInfo(strings.NewReader(fixture), int64(len(fixture)))
+	assert.Equal(t, err, nil, "problem parsing last game")
+	assert.Equal(t, gi, games[len(games)-1].GameInfo, "wrong game found")
+}
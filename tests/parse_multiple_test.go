@@ -20,3 +20,26 @@ func TestParseMultiple(t *testing.T) {
 	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], "Hashimoto Utaro",  "wrong black player name")
 	assert.Equal(t, game.GameInfo[sgf.Date],            "1943-05-05,06,07", "wrong date")
 }
+
+func TestParseCollection(t *testing.T) {
+	games, err := parse.ParseCollection("(;GM[1]PB[Alice];B[pd])\n\n  (;GM[1]PB[Bob];B[dd];W[pp])\n")
+	assert.Equal(t, err, nil, "problem parsing collection")
+	assert.Equal(t, len(games), 2, "wrong number of games")
+	assert.Equal(t, games[0].GameInfo[sgf.PlayerBlackName], "Alice", "wrong first game")
+	assert.Equal(t, games[1].GameInfo[sgf.PlayerBlackName], "Bob", "wrong second game")
+	assert.Equal(t, games[1].NodeCount(), 3, "wrong second game tree")
+
+	games, err = parse.ParseCollection(" \n")
+	assert.Equal(t, err, nil, "empty collection is not an error")
+	assert.Equal(t, len(games), 0, "empty collection has games")
+
+	_, err = parse.ParseCollection("(;GM[1]PB[Alice];B[pd])(;GM[1]PB[Bob];B[dd")
+	assert.NotEqual(t, err, nil, "expected an error")
+}
+
+func TestParseCollectionSingleGame(t *testing.T) {
+	games, err := parse.ParseCollection("(;GM[1]PB[Alice];B[pd];W[dd])\r\n\t ")
+	assert.Equal(t, err, nil, "problem parsing collection")
+	assert.Equal(t, len(games), 1, "wrong number of games")
+	assert.Equal(t, games[0].NodeCount(), 3, "wrong game tree")
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/fixtures"
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCollectionParallel(t *testing.T) {
+	fixture, err := fixtures.Sgf("honinbo.sgf")
+	assert.Equal(t, err, nil, "problem loading fixture")
+
+	// Shift_JIS second bytes 0x5C and 0x5D look like '\' and ']'
+	shiftJIS := "(;CA[Shift_JIS]PB[\x95\x5c]PW[\x83\x5d];B[aa])(;CA[Shift_JIS]C[\x83\x5d(];B[bb])"
+
+	for _, input := range []string{fixture, shiftJIS} {
+		sequential, err := parse.ParseCollection(input)
+		assert.Equal(t, err, nil, "problem parsing collection")
+		parallel, err := parse.ParseCollectionParallel(input, 4)
+		assert.Equal(t, err, nil, "problem parsing collection in parallel")
+
+		assert.Equal(t, len(parallel), len(sequential), "wrong number of games")
+		for i := range sequential {
+			assert.Equal(t, parallel[i].String(), sequential[i].String(), "games out of order")
+		}
+	}
+
+	_, err = parse.ParseCollectionParallel("(;GM[1];B[aa])(;GM[1];B[bb]W)", 2)
+	_, expected := parse.ParseCollection("(;GM[1];B[aa])(;GM[1];B[bb]W)")
+	assert.Equal(t, err, expected, "errors should match")
+}
+
+func BenchmarkParse(b *testing.B) {
+	fixture, _ := fixtures.Sgf("honinbo.sgf")
+	for i := 0; i < b.N; i++ {
+		parse.Parse(fixture)
+	}
+}
+
+func BenchmarkParseCollectionParallel(b *testing.B) {
+	fixture, _ := fixtures.Sgf("honinbo.sgf")
+	for i := 0; i < b.N; i++ {
+		parse.ParseCollectionParallel(fixture, 4)
+	}
+}
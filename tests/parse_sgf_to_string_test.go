@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/dhodges/sgfinfo/fixtures"
 	"github.com/dhodges/sgfinfo/sgf"
 	"github.com/dhodges/sgfinfo/parse"
   "github.com/stretchr/testify/assert"
@@ -54,3 +56,113 @@ func TestVariationsToString(t *testing.T) {
 	game := games[0]
 	assert.Equal(t, game.String(), gameStr, "error writing SGF to string")
 }
+
+func TestHandicapRootToString(t *testing.T) {
+	gameStr := "(;HA[2]SZ[19]AB[dd][pp];W[qp];B[dp])"
+
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	assert.Equal(t, len(game.Setup), 2, "wrong number of setup stones")
+	assert.Equal(t, game.NodeCount(), 3, "setup stones parsed as a node")
+	assert.Equal(t, game.String(), gameStr, "error writing SGF to string")
+}
+
+func TestSerializeStampFormat(t *testing.T) {
+	game := new(sgf.Game)
+	game.GameInfo = make(sgf.GameInfo)
+	game.AddInfo(sgf.Property{Name: sgf.PlayerBlackName, Value: "Alice"})
+	game.GameTree = new(sgf.Node)
+	game.GameTree.AddProperty(sgf.Property{Name: "B", Value: "pd"})
+
+	assert.Equal(t, game.Serialize(sgf.SerializeOptions{StampFormat: true}), "(;FF[4]GM[1]PB[Alice];B[pd])", "format not stamped")
+	assert.Equal(t, game.String(), "(;PB[Alice];B[pd])", "plain output stamped")
+
+	game.AddInfo(sgf.Property{Name: sgf.FileFormat, Value: "3"})
+	assert.Equal(t, game.Serialize(sgf.SerializeOptions{StampFormat: true}), "(;FF[3]GM[1]PB[Alice];B[pd])", "existing format replaced")
+}
+
+func TestSerializePasses(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[19];B[pd];W[];B[tt](;W[dd])(;W[]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	assert.Equal(t, game.String(), "(;SZ[19];B[pd];W[];B[](;W[dd])(;W[]))", "passes not written empty")
+	assert.Equal(t, game.Serialize(sgf.SerializeOptions{PassAsTT: true}), "(;SZ[19];B[pd];W[tt];B[tt](;W[dd])(;W[tt]))", "passes not written as tt")
+	assert.Equal(t, game.GameTree.Next.Point.Value, "", "game changed by serializing")
+
+	games, err = parse.ParseString("(;SZ[21];B[tt];W[])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].Serialize(sgf.SerializeOptions{PassAsTT: true}), "(;SZ[21];B[tt];W[])", "tt used on a large board")
+}
+
+func TestWriteFF3(t *testing.T) {
+	games, err := parse.ParseString("(;FF[4]CA[UTF-8]SZ[19]AB[aa:ab];B[pd]MA[qc];W[]AR[aa:cc];B[tt])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	for n := 0; n < 2; n++ {
+		var buf bytes.Buffer
+		err = games[0].WriteFF3(&buf)
+		assert.Equal(t, err, nil, "problem writing FF[3]")
+		assert.Equal(t, buf.String(), "(;FF[3]SZ[19]AB[aa][ab];B[pd]M[qc];W[tt];B[tt])", "wrong FF[3] output")
+	}
+	assert.Equal(t, len(games[0].FF3Warnings()), 2, "dropped properties not reported")
+	assert.Equal(t, len(games[0].Warnings), 0, "warnings added to the game")
+}
+
+func TestMultipleValuesToString(t *testing.T) {
+	gameStr := "(;GM[1]TR[aa][bb];B[pd]AB[dd][ee][ff]TR[pd]SQ[qc][qd];W[dp])"
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	node := games[0].GameTree
+	assert.Equal(t, len(node.Properties), 6, "values dropped")
+	assert.Equal(t, games[0].String(), gameStr, "multiple values not kept together")
+}
+
+func TestWriteToRoundTrip(t *testing.T) {
+	games, err := parseFixture("2014.07.06_WAGC-Rd1-Lithuania-Canada-var.sgf")
+	assert.Equal(t, err, nil, "problem loading fixture")
+
+	var buf bytes.Buffer
+	_, err = games[0].WriteTo(&buf)
+	assert.Equal(t, err, nil, "problem writing game")
+
+	reparsed, err := parse.ParseString(buf.String())
+	assert.Equal(t, err, nil, "problem parsing written game")
+	assert.Equal(t, reparsed[0].GameInfo, games[0].GameInfo, "game info changed")
+	assert.Equal(t, reparsed[0].GameTree.Equal(games[0].GameTree), true, "game tree changed")
+	assert.Equal(t, reparsed[0].String(), buf.String(), "output not stable")
+}
+
+func TestCommentNewlinesRoundTrip(t *testing.T) {
+	gameStr := "(;GC[line one\nline two];B[pd]C[First paragraph.\n\nSecond paragraph.];W[dp])"
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].String(), gameStr, "comment line breaks lost")
+}
+
+func TestEscapedValuesRoundTrip(t *testing.T) {
+	gameStr := `(;GC[a \\ backslash];B[pd]C[he said \]end[ of it];W[dp])`
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	assert.Equal(t, games[0].GameInfo["GC"], `a \ backslash`, "escape not removed")
+	assert.Equal(t, games[0].GameTree.Properties[0].Value, "he said ]end[ of it", "escapes not removed")
+	assert.Equal(t, games[0].String(), gameStr, "values not escaped again")
+}
+
+func TestCollectionRoundTrip(t *testing.T) {
+	fixture, err := fixtures.Sgf("honinbo.sgf")
+	assert.Equal(t, err, nil, "problem loading fixture")
+	games, err := parse.ParseCollection(fixture)
+	assert.Equal(t, err, nil, "problem parsing fixture")
+
+	for _, game := range games {
+		reparsed, err := parse.ParseString(game.String())
+		assert.Equal(t, err, nil, "problem parsing written game")
+		assert.Equal(t, reparsed[0].NodeCount(), game.NodeCount(), "node count changed")
+		assert.Equal(t, reparsed[0].GameInfo, game.GameInfo, "game info changed")
+		assert.Equal(t, reparsed[0].GameTree.Equal(game.GameTree), true, "game tree changed")
+	}
+}
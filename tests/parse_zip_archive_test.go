@@ -33,7 +33,7 @@ func TestParsingZipArchiveSGFfile(t *testing.T) {
 	game := games[0]
 	assert.Equal(t, game.GameInfo[sgf.PlayerWhiteName], "Ito Showa", "wrong white player name found")
 	assert.Equal(t, game.GameInfo[sgf.PlayerBlackName], "Kuwahara Shusaku", "wrong black player name found")
-	assert.Equal(t, game.NodeCount(), 202,              "wrong node count")
+	assert.Equal(t, game.NodeCount(), 203,              "wrong node count")
 }
 
 func TestParsingZipArchiveAllSGFfiles(t *testing.T) {
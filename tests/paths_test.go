@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+var branchedGame = "(;GM[1];B[pd](;W[dp];B[pp])(;W[dd];B[pp];W[dp]))"
+
+func move(color sgf.Color, point string) sgf.Move {
+	return sgf.Move{Color: color, Point: sgf.Point{X: rune(point[0]), Y: rune(point[1])}}
+}
+
+func TestFindLine(t *testing.T) {
+	games, err := parse.ParseString(branchedGame)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	path, ok := games[0].FindLine([]sgf.Move{move(sgf.Black, "pd"), move(sgf.White, "dd"), move(sgf.Black, "pp")})
+	assert.Equal(t, ok, true, "line not found")
+	assert.Equal(t, path, []int{1}, "wrong path")
+
+	_, ok = games[0].FindLine([]sgf.Move{move(sgf.Black, "pd"), move(sgf.White, "pp")})
+	assert.Equal(t, ok, false, "unexpected line found")
+}
+
+func TestDetachVariation(t *testing.T) {
+	games, err := parse.ParseString("(;PB[Alice]SZ[19];B[pd];W[dp](;B[pp];W[dd])(;B[dd];W[pp]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	detached, err := game.DetachVariation(game.GameTree.Next, 1)
+	assert.Equal(t, err, nil, "problem detaching variation")
+
+	assert.Equal(t, detached.String(), "(;PB[Alice]SZ[19]AB[pd]AW[dp];B[dd];W[pp])", "wrong detached game")
+	assert.Equal(t, game.String(), "(;PB[Alice]SZ[19];B[pd];W[dp](;B[pp];W[dd]))", "variation not removed")
+
+	_, err = game.DetachVariation(game.GameTree.Next, 1)
+	assert.NotEqual(t, err, nil, "expected an error")
+}
+
+func TestBoardAtPath(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[9];B[cc](;W[gg];B[gc])(;W[cg];B[gg];W[gc]))")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	board, err := games[0].BoardAtPath([]int{1})
+	assert.Equal(t, err, nil, "problem replaying variation")
+
+	expected := sgf.NewBoard(9)
+	playMoves(expected, sgf.Black, "cc", "gg")
+	playMoves(expected, sgf.White, "cg", "gc")
+	assert.Equal(t, board.String(), expected.String(), "wrong position")
+
+	node, err := games[0].NodeAtPath([]int{1})
+	assert.Equal(t, err, nil, "problem finding node")
+	assert.Equal(t, node.Point.String(), "W[gc]", "wrong node")
+
+	_, err = games[0].BoardAtPath([]int{2})
+	assert.NotEqual(t, err, nil, "expected an error")
+}
+
+func TestChapters(t *testing.T) {
+	games, err := parse.ParseString("(;PB[Alice]SZ[9];B[cc];W[gg];B[cg];W[gc];B[ee]C[five];W[ec];B[ce];W[ge];B[eg];W[dd])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	chapters := games[0].Chapters(5)
+	assert.Equal(t, len(chapters), 2, "wrong number of chapters")
+	assert.Equal(t, chapters[0].String(), "(;PB[Alice]SZ[9];B[cc];W[gg];B[cg];W[gc];B[ee]C[five])", "wrong first chapter")
+	assert.Equal(t, chapters[1].String(), "(;PB[Alice]SZ[9]AB[cc][ee][cg]AW[gc][gg];W[ec];B[ce];W[ge];B[eg];W[dd])", "wrong second chapter")
+
+	games, err = parse.ParseString("(;SZ[9]AB[aa];B[cc]C[one]TR[cc];W[gg])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	before := games[0].String()
+	chapter := games[0].Chapters(5)[0]
+	chapter.Setup[0].Value = "bb"
+	chapter.Setup = append(chapter.Setup, sgf.Property{Name: "AW", Value: "cc"})
+	chapter.GameTree.Properties[0].Value = "changed"
+	chapter.GameTree.Properties = append(chapter.GameTree.Properties[:1], sgf.Property{Name: "SQ", Value: "dd"})
+	assert.Equal(t, games[0].String(), before, "editing a chapter changed the game")
+}
+
+func TestSiblingsOf(t *testing.T) {
+	games, err := parse.ParseString(branchedGame)
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	game := games[0]
+	branch := game.GameTree
+	second := branch.Variations[len(branch.Variations)-1]
+	siblings, i := game.SiblingsOf(second)
+	assert.Equal(t, len(siblings), 2, "wrong number of siblings")
+	assert.Equal(t, i, 1, "wrong index")
+	assert.Equal(t, siblings[0].Point.String(), "W[dp]", "wrong first sibling")
+	assert.Equal(t, siblings[1] == second, true, "wrong second sibling")
+
+	siblings, i = game.SiblingsOf(second.Next)
+	assert.Equal(t, len(siblings), 1, "wrong number of siblings off a branch")
+	assert.Equal(t, i, 0, "wrong index off a branch")
+
+	_, i = game.SiblingsOf(&sgf.Node{})
+	assert.Equal(t, i, -1, "node outside the game found")
+}
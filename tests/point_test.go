@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+// numericCodec writes points as 1-based "column-row" numbers.
+type numericCodec struct{}
+
+func (numericCodec) Encode(p sgf.Point) string {
+	return fmt.Sprintf("%d-%d", p.X-'a'+1, p.Y-'a'+1)
+}
+
+func (numericCodec) Decode(value string) (sgf.Point, error) {
+	var x, y int
+	if _, err := fmt.Sscanf(value, "%d-%d", &x, &y); err != nil || x < 1 || y < 1 || x > 26 || y > 26 {
+		return sgf.Point{}, errors.New("invalid point: " + value)
+	}
+	return sgf.Point{X: 'a' + rune(x-1), Y: 'a' + rune(y-1)}, nil
+}
+
+func TestParsePoint(t *testing.T) {
+	point, err := sgf.ParsePoint("pd")
+	assert.Equal(t, err, nil, "problem parsing point")
+	assert.Equal(t, point, sgf.Point{X: 'p', Y: 'd'}, "wrong point")
+	assert.Equal(t, point.SGF(), "pd", "wrong encoding")
+
+	_, err = sgf.ParsePoint("p4")
+	assert.NotEqual(t, err, nil, "expected an error")
+}
+
+func TestPropertyPoint(t *testing.T) {
+	point, err := sgf.Property{Name: "B", Value: "pD"}.Point()
+	assert.Equal(t, err, nil, "problem reading point")
+	assert.Equal(t, point, sgf.Point{X: 'p', Y: 'D'}, "wrong point")
+
+	_, err = sgf.Property{Name: "B", Value: ""}.Point()
+	assert.Equal(t, err, sgf.ErrPass, "empty value not a pass")
+
+	for _, value := range []string{"p", "pdq", "p4"} {
+		_, err = sgf.Property{Name: "B", Value: value}.Point()
+		assert.NotEqual(t, err, nil, "expected an error for "+value)
+		assert.NotEqual(t, err, sgf.ErrPass, "malformed value taken as a pass")
+	}
+}
+
+func TestPointCodec(t *testing.T) {
+	point, err := numericCodec{}.Decode("16-4")
+	assert.Equal(t, err, nil, "problem decoding point")
+	assert.Equal(t, point, sgf.Point{X: 'p', Y: 'd'}, "wrong point")
+
+	gameStr := "(;SZ[19]AB[3-3];B[16-4];W[4-16]TR[16-4];B[])"
+	games := parse.ParseWithConfig(gameStr, parse.ParserConfig{PointCodec: numericCodec{}})
+	assert.Equal(t, len(games[0].Errors), 0, "problem parsing game string")
+	assert.Equal(t, games[0].Tenukis(10), []int{2}, "moves not decoded")
+	assert.Equal(t, games[0].String(), "(;SZ[19]AB[cc];B[pd];W[dp]TR[pd];B[])", "moves not stored as letters")
+	assert.Equal(t, games[0].Serialize(sgf.SerializeOptions{PointCodec: numericCodec{}}), gameStr, "moves not written back")
+
+	point, err = sgf.ParsePoint("pd")
+	assert.Equal(t, err, nil, "codec should not change ParsePoint")
+	assert.Equal(t, point.SGF(), "pd", "codec should not change SGF")
+}
+
+func TestPointCoords(t *testing.T) {
+	col, row := sgf.Point{X: 'p', Y: 'd'}.Coords()
+	assert.Equal(t, []int{col, row}, []int{15, 3}, "wrong coordinates")
+
+	col, row = sgf.Point{X: 'a', Y: 'Z'}.Coords()
+	assert.Equal(t, []int{col, row}, []int{0, 51}, "wrong coordinates")
+}
+
+func TestMovePasses(t *testing.T) {
+	games, err := parse.ParseString("(;GM[1]SZ[19];B[pd];W[dd];B[];W[tt];B[])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	node := games[0].GameTree
+	for node.Next != nil {
+		node = node.Next
+	}
+	color, _, isPass := node.Move()
+	assert.Equal(t, color, "B", "wrong colour")
+	assert.Equal(t, isPass, true, "last move not a pass")
+	assert.Equal(t, node.Point.IsPass(19), true, "last move not a pass")
+
+	assert.Equal(t, sgf.Property{Name: "W", Value: "tt"}.IsPass(19), true, "tt not a pass on 19x19")
+	assert.Equal(t, sgf.Property{Name: "W", Value: "tt"}.IsPass(21), false, "tt a pass on 21x21")
+	assert.Equal(t, games[0].GameTree.Point.IsPass(19), false, "move taken as a pass")
+}
+
+func TestPointDistance(t *testing.T) {
+	p := sgf.Point{X: 'd', Y: 'd'}
+	tests := []struct {
+		q         sgf.Point
+		manhattan int
+		euclidean float64
+	}{
+		{sgf.Point{X: 'd', Y: 'd'}, 0, 0},
+		{sgf.Point{X: 'd', Y: 'e'}, 1, 1},
+		{sgf.Point{X: 'e', Y: 'e'}, 2, math.Sqrt2},
+		{sgf.Point{X: 'p', Y: 'p'}, 24, 12 * math.Sqrt2},
+		{sgf.Point{X: 'g', Y: 'h'}, 7, 5},
+	}
+	for _, test := range tests {
+		assert.Equal(t, p.Distance(test.q), test.manhattan, "wrong Manhattan distance to "+test.q.String())
+		assert.Equal(t, test.q.Distance(p), test.manhattan, "distance not symmetric")
+		assert.True(t, math.Abs(p.EuclideanDistance(test.q)-test.euclidean) < 1e-9, "wrong Euclidean distance to "+test.q.String())
+	}
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyInfo(t *testing.T) {
+	spec, ok := sgf.PropertyInfo("AB")
+	assert.Equal(t, ok, true, "AB not found")
+	assert.Equal(t, spec, sgf.PropertySpec{ValueType: "stone", List: true, NodeType: sgf.SetupNode, Group: "setup"}, "wrong AB spec")
+
+	spec, ok = sgf.PropertyInfo("C")
+	assert.Equal(t, ok, true, "C not found")
+	assert.Equal(t, spec, sgf.PropertySpec{ValueType: "text", NodeType: sgf.AnyNode}, "wrong C spec")
+
+	spec, ok = sgf.PropertyInfo("SZ")
+	assert.Equal(t, ok, true, "SZ not found")
+	assert.Equal(t, spec, sgf.PropertySpec{ValueType: "number|number:number", NodeType: sgf.RootNode}, "wrong SZ spec")
+
+	_, ok = sgf.PropertyInfo("XX")
+	assert.Equal(t, ok, false, "unknown property found")
+}
@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/dhodges/sgfinfo/sgf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAreaScore(t *testing.T) {
+	// black walls off the a column and white the e column; the dead white
+	// stone at aa is marked as black territory. Black passes twice and
+	// white once.
+	games, err := parse.ParseString("(;SZ[5]KM[0.5];B[ba];W[da];B[bb];W[db];B[bc];W[dc];B[bd];W[dd];B[be];W[de]" +
+		";B[];W[aa];B[];W[]TB[aa][ab][ac][ad][ae]TW[ea][eb][ec][ed][ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	score, err := games[0].AreaScore(sgf.AreaScoreOptions{})
+	assert.Equal(t, err, nil, "problem scoring game")
+	assert.Equal(t, score, -0.5, "wrong area score")
+
+	score, err = games[0].AreaScore(sgf.AreaScoreOptions{CountPassStones: true})
+	assert.Equal(t, err, nil, "problem scoring game")
+	assert.Equal(t, score, -1.5, "pass stones not counted")
+
+	games, err = parse.ParseString("(;SZ[5]KM[0.5];B[ba];W[da];B[bb];W[db];B[bc];W[dc];B[bd];W[dd];B[be];W[de];B[];W[aa];B[];W[])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+	score, err = games[0].AreaScore(sgf.AreaScoreOptions{})
+	assert.Equal(t, err, nil, "problem scoring game")
+	assert.Equal(t, score, -6.5, "unmarked stones should be alive")
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dhodges/sgfinfo/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func validate(t *testing.T, gameStr string) []error {
+	games, err := parse.ParseString(gameStr)
+	assert.Equal(t, err, nil, "problem parsing game string")
+	return games[0].Validate()
+}
+
+func TestValidateKomi(t *testing.T) {
+	assert.Equal(t, len(validate(t, "(;KM[6.5];B[pd])")), 0, "6.5 komi is valid")
+	assert.Equal(t, len(validate(t, "(;KM[7];B[pd])")), 0, "7 komi is valid")
+
+	warnings := validate(t, "(;KM[6.3];B[pd])")
+	assert.Equal(t, len(warnings), 1, "6.3 komi should be flagged")
+	assert.Equal(t, warnings[0].Error(), "KM[6.3]: komi is not a whole or half point", "wrong warning")
+}
+
+func TestValidateMarkupOnBoard(t *testing.T) {
+	warnings := validate(t, "(;SZ[9];B[cc]TR[dd]LB[ee:A];W[gg]TR[kk]AR[aa:ii])")
+	assert.Equal(t, len(warnings), 2, "off-board markup should be flagged")
+	assert.Equal(t, warnings[0].Error(), "SZ[9]: points used need a board of at least 11", "wrong warning")
+	assert.Equal(t, warnings[1].Error(), "node 2: TR[kk]: point off the board", "wrong warning")
+
+	assert.Equal(t, len(validate(t, "(;SZ[19];B[cc]TR[kk])")), 0, "markup is on the board")
+
+	warnings = validate(t, "(;SZ[9]TR[jj]LB[aa:A];B[cc])")
+	assert.Equal(t, len(warnings), 2, "off-board root markup should be flagged")
+	assert.Equal(t, warnings[1].Error(), "node 0: TR[jj]: point off the board", "wrong warning")
+}
+
+func TestValidateFirstMove(t *testing.T) {
+	assert.Equal(t, len(validate(t, "(;SZ[19];B[pd];W[dp])")), 0, "black first is expected")
+	assert.Equal(t, len(validate(t, "(;SZ[19]HA[2]AB[dd]AB[pp];W[dp])")), 0, "white first in a handicap game is expected")
+
+	warnings := validate(t, "(;SZ[19];C[start];W[pd];B[dp])")
+	assert.Equal(t, len(warnings), 1, "white first in an even game should be flagged")
+	assert.Equal(t, warnings[0].Error(), "node 2: W[pd]: white moves first in an even game", "wrong warning")
+}
+
+func TestValidateSetupPoints(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[9]AB[aa][aa]AW[bb];B[cc])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	warnings := games[0].Validate()
+	assert.Equal(t, len(warnings), 1, "duplicate point should be flagged")
+	assert.Equal(t, warnings[0].Error(), "node 0: AB: duplicate point [aa]", "wrong warning")
+	assert.Equal(t, games[0].String(), "(;SZ[9]AB[aa][aa]AW[bb];B[cc])", "setup stones changed")
+
+	warnings = validate(t, "(;SZ[9];B[cc];AW[aa:bb][ba])")
+	assert.Equal(t, len(warnings), 1, "duplicate point in a range should be flagged")
+	assert.Equal(t, warnings[0].Error(), "node 2: AW: duplicate point [ba]", "wrong warning")
+
+	assert.Equal(t, len(validate(t, "(;SZ[9]AB[aa]AW[aa];B[cc])")), 0, "points differ by property")
+}
+
+func TestValidateBoardSize(t *testing.T) {
+	assert.Equal(t, len(validate(t, "(;SZ[19];B[pd])")), 0, "19x19 is valid")
+	assert.Equal(t, len(validate(t, "(;SZ[19:13];B[pd])")), 0, "19x13 is valid")
+
+	warnings := validate(t, "(;SZ[0];B[aa])")
+	assert.Equal(t, len(warnings), 1, "SZ[0] should be flagged")
+	assert.Equal(t, warnings[0].Error(), "SZ[0]: board size outside 1..52", "wrong warning")
+
+	warnings = validate(t, "(;SZ[100];B[aa])")
+	assert.Equal(t, len(warnings), 1, "SZ[100] should be flagged")
+	assert.Equal(t, warnings[0].Error(), "SZ[100]: board size outside 1..52", "wrong warning")
+}
+
+func TestValidateInferredSize(t *testing.T) {
+	games, err := parse.ParseString("(;SZ[9];B[pd];W[dp];B[ss];W[tt])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	size, ok := games[0].InferBoardSize()
+	assert.Equal(t, ok, true, "points not found")
+	assert.Equal(t, size, 19, "wrong inferred size")
+
+	warnings := games[0].Validate()
+	assert.Equal(t, len(warnings), 1, "size mismatch should be flagged")
+	assert.Equal(t, warnings[0].Error(), "SZ[9]: points used need a board of at least 19", "wrong warning")
+
+	assert.Equal(t, len(validate(t, "(;SZ[19];B[pd];W[dp])")), 0, "points fit the board")
+}
+
+func TestVerifyTerritory(t *testing.T) {
+	// black walls off the left of the board on the c column, white the
+	// right on the d column
+	moves := ""
+	for y := 'a'; y <= 'e'; y++ {
+		moves += fmt.Sprintf(";B[c%c];W[d%c]", y, y)
+	}
+	games, err := parse.ParseString("(;GM[1]SZ[5]" + moves + ";TB[aa][bb][da]TW[ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	problems, err := games[0].VerifyTerritory()
+	assert.Equal(t, err, nil, "problem replaying game")
+	assert.Equal(t, problems, []string{"TB[da]: point holds a white stone"}, "wrong problems")
+
+	games, err = parse.ParseString("(;GM[1]SZ[5]" + moves + ";TB[ee])")
+	assert.Equal(t, err, nil, "problem parsing game string")
+
+	problems, err = games[0].VerifyTerritory()
+	assert.Equal(t, err, nil, "problem replaying game")
+	assert.Equal(t, problems, []string{"TB[ee]: region borders white stones"}, "wrong problems")
+}